@@ -3,6 +3,10 @@
 // @description REST-сервис для агрегации данных об онлайн-подписках пользователей
 // @host localhost:8080
 // @BasePath /
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description API key or token as "Bearer <key>"
 package main
 
 import (
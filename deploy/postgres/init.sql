@@ -0,0 +1,11 @@
+-- Runs once, when the compose database volume is created. The service
+-- connects as subscriptions_app rather than as the postgres superuser, which
+-- bypasses the row-level security policies that keep tenants apart. The role
+-- owns the database so that it can run the migrations, and the tenant tables
+-- use FORCE ROW LEVEL SECURITY, so the policies apply to their owner too.
+CREATE ROLE subscriptions_app LOGIN PASSWORD 'subscriptions_app'
+    NOSUPERUSER NOBYPASSRLS NOCREATEDB NOCREATEROLE;
+ALTER DATABASE subscriptions OWNER TO subscriptions_app;
+-- The first migration creates the extension if it is missing; creating it
+-- here does not depend on the role being allowed to.
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
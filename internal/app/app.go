@@ -1,37 +1,128 @@
 package app
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/database/sqlite"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 	httpSwagger "github.com/swaggo/http-swagger"
 
 	_ "github.com/golangtestcases/subscribe-service/docs"
+	"github.com/golangtestcases/subscribe-service/internal/app/handlers/batch_subscriptions_handler"
+	"github.com/golangtestcases/subscribe-service/internal/app/handlers/create_budget_handler"
 	"github.com/golangtestcases/subscribe-service/internal/app/handlers/create_subscription_handler"
+	"github.com/golangtestcases/subscribe-service/internal/app/handlers/create_webhook_handler"
+	"github.com/golangtestcases/subscribe-service/internal/app/handlers/delete_budget_handler"
 	"github.com/golangtestcases/subscribe-service/internal/app/handlers/delete_subscription_handler"
+	"github.com/golangtestcases/subscribe-service/internal/app/handlers/delete_webhook_handler"
+	"github.com/golangtestcases/subscribe-service/internal/app/handlers/export_subscriptions_handler"
+	"github.com/golangtestcases/subscribe-service/internal/app/handlers/find_duplicates_handler"
+	"github.com/golangtestcases/subscribe-service/internal/app/handlers/find_price_anomalies_handler"
+	"github.com/golangtestcases/subscribe-service/internal/app/handlers/get_budget_handler"
 	"github.com/golangtestcases/subscribe-service/internal/app/handlers/get_cost_handler"
+	"github.com/golangtestcases/subscribe-service/internal/app/handlers/get_renewals_calendar_handler"
 	"github.com/golangtestcases/subscribe-service/internal/app/handlers/get_subscription_handler"
+	"github.com/golangtestcases/subscribe-service/internal/app/handlers/get_subscription_members_handler"
+	"github.com/golangtestcases/subscribe-service/internal/app/handlers/get_webhook_handler"
+	"github.com/golangtestcases/subscribe-service/internal/app/handlers/import_subscriptions_handler"
+	"github.com/golangtestcases/subscribe-service/internal/app/handlers/issue_api_key_handler"
+	"github.com/golangtestcases/subscribe-service/internal/app/handlers/list_alerts_handler"
+	"github.com/golangtestcases/subscribe-service/internal/app/handlers/list_api_keys_handler"
+	"github.com/golangtestcases/subscribe-service/internal/app/handlers/list_budgets_handler"
 	"github.com/golangtestcases/subscribe-service/internal/app/handlers/list_subscriptions_handler"
+	"github.com/golangtestcases/subscribe-service/internal/app/handlers/list_webhook_deliveries_handler"
+	"github.com/golangtestcases/subscribe-service/internal/app/handlers/list_webhooks_handler"
+	"github.com/golangtestcases/subscribe-service/internal/app/handlers/liveness_handler"
+	"github.com/golangtestcases/subscribe-service/internal/app/handlers/metrics_handler"
+	"github.com/golangtestcases/subscribe-service/internal/app/handlers/readiness_handler"
+	"github.com/golangtestcases/subscribe-service/internal/app/handlers/revoke_api_key_handler"
+	"github.com/golangtestcases/subscribe-service/internal/app/handlers/suggest_subscriptions_handler"
+	"github.com/golangtestcases/subscribe-service/internal/app/handlers/update_budget_handler"
 	"github.com/golangtestcases/subscribe-service/internal/app/handlers/update_subscription_handler"
+	"github.com/golangtestcases/subscribe-service/internal/app/handlers/update_subscription_members_handler"
+	"github.com/golangtestcases/subscribe-service/internal/app/jobs/duplicate_detection_job"
+	"github.com/golangtestcases/subscribe-service/internal/app/jobs/outbox_dispatch_job"
+	"github.com/golangtestcases/subscribe-service/internal/app/jobs/renewal_notification_job"
+	"github.com/golangtestcases/subscribe-service/internal/app/jobs/replica_check_job"
+	"github.com/golangtestcases/subscribe-service/internal/app/jobs/webhook_delivery_job"
+	apiKeyRepository "github.com/golangtestcases/subscribe-service/internal/domain/apikey/repository"
+	apiKeyService "github.com/golangtestcases/subscribe-service/internal/domain/apikey/service"
+	budgetRepository "github.com/golangtestcases/subscribe-service/internal/domain/budget/repository"
+	budgetService "github.com/golangtestcases/subscribe-service/internal/domain/budget/service"
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	outboxRepository "github.com/golangtestcases/subscribe-service/internal/domain/outbox/repository"
+	outboxService "github.com/golangtestcases/subscribe-service/internal/domain/outbox/service"
 	"github.com/golangtestcases/subscribe-service/internal/domain/subscription/repository"
 	"github.com/golangtestcases/subscribe-service/internal/domain/subscription/service"
+	webhookRepository "github.com/golangtestcases/subscribe-service/internal/domain/webhook/repository"
+	webhookService "github.com/golangtestcases/subscribe-service/internal/domain/webhook/service"
+	"github.com/golangtestcases/subscribe-service/internal/infra/auth"
 	"github.com/golangtestcases/subscribe-service/internal/infra/config"
+	"github.com/golangtestcases/subscribe-service/internal/infra/database"
+	"github.com/golangtestcases/subscribe-service/internal/infra/health"
 	"github.com/golangtestcases/subscribe-service/internal/infra/http/middlewares"
+	"github.com/golangtestcases/subscribe-service/internal/infra/metrics"
+	"github.com/golangtestcases/subscribe-service/internal/infra/outbox"
+	"github.com/golangtestcases/subscribe-service/internal/infra/tenant"
+	"github.com/golangtestcases/subscribe-service/internal/infra/tracing"
+	"github.com/golangtestcases/subscribe-service/internal/infra/webhook"
+	"github.com/google/uuid"
 )
 
 type App struct {
-	config *config.Config
-	server http.Server
-	db     *sql.DB
-	logger *slog.Logger
+	config  *config.Config
+	server  http.Server
+	db      *sql.DB
+	replica *sql.DB
+	spans   tracing.Exporter
+	health  *health.Checker
+	logger  *slog.Logger
+	jobs    []job
+}
+
+// job is a background worker started together with the server.
+type job interface {
+	Run(ctx context.Context)
+}
+
+// services are the domain services the handlers and jobs are built from.
+// Services that need PostgreSQL are nil with SQLite and in-memory storage.
+type services struct {
+	subscription tracedSubscriptionService
+	budget       *budgetService.BudgetService
+	webhook      *webhookService.WebhookService
+	outbox       *outboxService.OutboxService
+	reads        *database.ReadRouter
+	apiKey       *apiKeyService.APIKeyService
+}
+
+// noBudgets stands in for the budget service when there are no budgets.
+type noBudgets struct{}
+
+func (noBudgets) EvaluateBudgets(context.Context, uuid.UUID) error {
+	return nil
+}
+
+// noEvents stands in for the outbox when no dispatcher would ever send or
+// prune the events, so that they are not stored at all.
+type noEvents struct{}
+
+func (noEvents) AddEvent(context.Context, model.Event) error {
+	return nil
 }
 
 func NewApp(configPath string) (*App, error) {
@@ -42,36 +133,224 @@ func NewApp(configPath string) (*App, error) {
 
 	logger := setupLogger(configImpl.Logger.Level)
 
-	db, err := setupDatabase(configImpl.Database)
+	app := &App{
+		config: configImpl,
+		logger: logger,
+	}
+
+	tenants, err := setupTenants(configImpl.Tenants)
 	if err != nil {
-		return nil, fmt.Errorf("setupDatabase: %w", err)
+		return nil, fmt.Errorf("setupTenants: %w", err)
+	}
+
+	var svc services
+	var checks []health.Check
+	switch configImpl.Storage {
+	case config.StorageMemory:
+		logger.Warn("using in-memory storage: data is lost on restart, budgets and webhooks are disabled")
+		svc = bootstrapMemoryServices(configImpl, logger)
+	case config.StorageSQLite:
+		logger.Warn("using SQLite storage: budgets and webhooks are disabled and events are not dispatched", "path", configImpl.SQLite.Path)
+
+		db, err := setupSQLite(configImpl.SQLite)
+		if err != nil {
+			return nil, fmt.Errorf("setupSQLite: %w", err)
+		}
+
+		version, err := runSQLiteMigrations(db)
+		if err != nil {
+			return nil, fmt.Errorf("runSQLiteMigrations: %w", err)
+		}
+
+		app.db = db
+		checks = databaseChecks(db, version)
+		svc = bootstrapSQLiteServices(db, configImpl, logger)
+	default:
+		db, err := setupDatabase(configImpl.Database)
+		if err != nil {
+			return nil, fmt.Errorf("setupDatabase: %w", err)
+		}
+
+		version, err := runMigrations(db)
+		if err != nil {
+			return nil, fmt.Errorf("runMigrations: %w", err)
+		}
+
+		var replica *sql.DB
+		if configImpl.Database.Replica.Enabled() {
+			// The replica is not pinged: reads use the primary until it
+			// passes the first check.
+			connector, err := pq.NewConnector(configImpl.Database.Replica.DSN())
+			if err != nil {
+				return nil, fmt.Errorf("failed to open replica: %w", err)
+			}
+			replica = sql.OpenDB(database.NewTenantConnector(connector))
+		}
+
+		app.db = db
+		app.replica = replica
+		svc = bootstrapServices(db, replica, configImpl, logger)
+
+		checks = databaseChecks(db, version)
+		if replica != nil {
+			checks = append(checks, health.Check{Name: "replica", Optional: true, Run: svc.reads.CheckReads})
+		}
 	}
 
-	if err := runMigrations(db); err != nil {
-		return nil, fmt.Errorf("runMigrations: %w", err)
+	app.health = health.NewChecker(configImpl.Server.ReadinessTimeout, checks...)
+	registry := metrics.NewRegistry()
+	if app.db != nil {
+		registry.Register(database.PoolMetrics(app.db, app.replica))
 	}
+	registry.Register(subscriptionMetrics(svc.subscription, tenants.Tenants()))
 
-	app := &App{
-		config: configImpl,
-		db:     db,
-		logger: logger,
+	var tracer *tracing.Tracer
+	if configImpl.Tracing.Exporter != "" {
+		exporter, err := setupSpanExporter(configImpl.Tracing)
+		if err != nil {
+			return nil, fmt.Errorf("setupSpanExporter: %w", err)
+		}
+
+		app.spans = exporter
+		tracer = tracing.NewTracer(exporter, func(err error) {
+			logger.Error("tracing failed", "error", err)
+		})
 	}
 
-	app.server.Handler = bootstrapHandler(db, logger)
+	var authenticator middlewares.Authenticator
+	if configImpl.Auth.Enabled {
+		authenticator = svc.apiKey
+		if configImpl.Auth.JWT.Enabled() {
+			verifier, err := setupJWTVerifier(configImpl.Auth.JWT, tenants)
+			if err != nil {
+				return nil, fmt.Errorf("setupJWTVerifier: %w", err)
+			}
+			authenticator = bearerAuthenticator{apiKeys: svc.apiKey, jwt: verifier}
+		}
+	} else {
+		logger.Warn("authentication is disabled: the API is open to anyone who can reach it")
+	}
+
+	app.server.Handler = bootstrapHandler(svc, app.health, registry, tracer, authenticator, tenants, configImpl.Tenants.Header, logger)
+	app.jobs = []job{
+		duplicate_detection_job.NewDuplicateDetectionJob(svc.subscription, tenants.Tenants(), configImpl.Jobs.DuplicateDetectionInterval, logger),
+	}
+	if svc.webhook != nil {
+		app.jobs = append(app.jobs, webhook_delivery_job.NewWebhookDeliveryJob(svc.webhook, tenants.Tenants(), configImpl.Webhooks.DeliveryInterval, logger))
+	}
+	if configImpl.Storage == config.StoragePostgres && configImpl.Database.Replica.Enabled() {
+		app.jobs = append(app.jobs, replica_check_job.NewReplicaCheckJob(svc.reads, configImpl.Database.Replica.CheckInterval, logger))
+	}
+	if svc.outbox != nil {
+		app.jobs = append(app.jobs, renewal_notification_job.NewRenewalNotificationJob(svc.subscription, tenants.Tenants(), configImpl.Jobs.RenewalCheckInterval, configImpl.Jobs.RenewalNoticePeriod, logger))
+		app.jobs = append(app.jobs, outbox_dispatch_job.NewOutboxDispatchJob(svc.outbox, tenants.Tenants(), configImpl.Outbox.DispatchInterval, logger))
+	}
 
 	return app, nil
 }
 
+// ListenAndServe serves requests and runs the background jobs until the
+// process receives SIGINT or SIGTERM, then shuts down gracefully: the listener
+// is closed, in-flight requests are given Server.ShutdownTimeout to finish, the
+// jobs are stopped and the database pools are closed. Requests still running
+// when the timeout expires are cut off. It returns nil after a graceful
+// shutdown.
 func (app *App) ListenAndServe() error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
 	address := fmt.Sprintf("%s:%s", app.config.Server.Host, app.config.Server.Port)
 	app.logger.Info("starting server", "address", address)
 
 	l, err := net.Listen("tcp", address)
 	if err != nil {
+		app.closeResources()
+		return err
+	}
+
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+
+	var jobs sync.WaitGroup
+	for _, j := range app.jobs {
+		jobs.Add(1)
+		go func() {
+			defer jobs.Done()
+			j.Run(jobsCtx)
+		}()
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- app.server.Serve(l)
+	}()
+
+	select {
+	case err := <-serveErr:
+		stopJobs()
+		jobs.Wait()
+		app.closeResources()
 		return err
+	case <-ctx.Done():
+	}
+
+	// A second signal kills the process without waiting.
+	stop()
+	app.logger.Info("shutting down",
+		"delay", app.config.Server.ShutdownDelay.String(),
+		"timeout", app.config.Server.ShutdownTimeout.String(),
+	)
+
+	// Keep serving while readiness fails, so that the orchestrator stops
+	// routing traffic here before the listener is closed.
+	app.health.SetShuttingDown()
+	time.Sleep(app.config.Server.ShutdownDelay)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), app.config.Server.ShutdownTimeout)
+	defer cancel()
+
+	var shutdownErr error
+	if err := app.server.Shutdown(shutdownCtx); err != nil {
+		app.logger.Warn("in-flight requests did not finish in time", "error", err)
+		shutdownErr = errors.Join(err, app.server.Close())
+	}
+
+	stopJobs()
+	jobsDone := make(chan struct{})
+	go func() {
+		jobs.Wait()
+		close(jobsDone)
+	}()
+	select {
+	case <-jobsDone:
+	case <-shutdownCtx.Done():
+		app.logger.Warn("background jobs did not stop in time")
+	}
+
+	if err := app.closeResources(); err != nil {
+		shutdownErr = errors.Join(shutdownErr, err)
 	}
 
-	return app.server.Serve(l)
+	if shutdownErr != nil {
+		return fmt.Errorf("failed to shut down gracefully: %w", shutdownErr)
+	}
+
+	app.logger.Info("server stopped")
+	return nil
+}
+
+// closeResources flushes the span exporter and closes the databases.
+func (app *App) closeResources() error {
+	var errs []error
+	if app.spans != nil {
+		errs = append(errs, app.spans.Close())
+	}
+	for _, db := range []*sql.DB{app.db, app.replica} {
+		if db != nil {
+			errs = append(errs, db.Close())
+		}
+	}
+	return errors.Join(errs...)
 }
 
 func setupLogger(level string) *slog.Logger {
@@ -96,11 +375,32 @@ func setupLogger(level string) *slog.Logger {
 	return slog.New(handler)
 }
 
+// setupTenants reads the tenants from the tenants file, or returns the single
+// default tenant if there is none.
+func setupTenants(cfg config.TenantsConfig) (*tenant.Directory, error) {
+	if cfg.File == "" {
+		return tenant.NewDirectory([]model.Tenant{{
+			ID:              tenant.DefaultID,
+			Name:            tenant.DefaultID,
+			DefaultCurrency: cfg.DefaultCurrency,
+		}}), nil
+	}
+
+	tenants, err := tenant.ReadFile(cfg.File, cfg.DefaultCurrency)
+	if err != nil {
+		return nil, fmt.Errorf("tenant.ReadFile: %w", err)
+	}
+	return tenant.NewDirectory(tenants), nil
+}
+
+// setupDatabase opens the PostgreSQL pool with every connection reporting the
+// tenant of each statement to the row-level security policies.
 func setupDatabase(cfg config.DatabaseConfig) (*sql.DB, error) {
-	db, err := sql.Open("postgres", cfg.DSN())
+	connector, err := pq.NewConnector(cfg.DSN())
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
+	db := sql.OpenDB(database.NewTenantConnector(connector))
 
 	if err := db.Ping(); err != nil {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
@@ -109,41 +409,307 @@ func setupDatabase(cfg config.DatabaseConfig) (*sql.DB, error) {
 	return db, nil
 }
 
-func runMigrations(db *sql.DB) error {
+func runMigrations(db *sql.DB) (uint, error) {
 	driver, err := postgres.WithInstance(db, &postgres.Config{})
 	if err != nil {
-		return fmt.Errorf("failed to create migration driver: %w", err)
+		return 0, fmt.Errorf("failed to create migration driver: %w", err)
 	}
 
 	m, err := migrate.NewWithDatabaseInstance("file://migrations", "postgres", driver)
 	if err != nil {
-		return fmt.Errorf("failed to create migration instance: %w", err)
+		return 0, fmt.Errorf("failed to create migration instance: %w", err)
 	}
 
+	return migrateUp(m)
+}
+
+func setupSQLite(cfg config.SQLiteConfig) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", cfg.DSN())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	return db, nil
+}
+
+func runSQLiteMigrations(db *sql.DB) (uint, error) {
+	driver, err := sqlite.WithInstance(db, &sqlite.Config{})
+	if err != nil {
+		return 0, fmt.Errorf("failed to create migration driver: %w", err)
+	}
+
+	m, err := migrate.NewWithDatabaseInstance("file://migrations/sqlite", "sqlite", driver)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create migration instance: %w", err)
+	}
+
+	return migrateUp(m)
+}
+
+// migrateUp applies all pending migrations and returns the resulting schema
+// version, which readiness expects the database to stay at.
+func migrateUp(m *migrate.Migrate) (uint, error) {
 	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-		return fmt.Errorf("failed to run migrations: %w", err)
+		return 0, fmt.Errorf("failed to run migrations: %w", err)
 	}
 
-	return nil
+	version, _, err := m.Version()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read migration version: %w", err)
+	}
+
+	return version, nil
+}
+
+func bootstrapServices(db, replica *sql.DB, cfg *config.Config, logger *slog.Logger) services {
+	budgetRepo := budgetRepository.NewPostgreSQLRepository(db)
+	budgetSvc := budgetService.NewBudgetService(budgetRepo)
+
+	webhookRepo := webhookRepository.NewPostgreSQLRepository(db)
+	webhookSvc := webhookService.NewWebhookService(
+		webhookRepo,
+		webhook.NewHTTPSender(cfg.Webhooks.Timeout),
+		webhookService.RetryPolicy{
+			MaxAttempts:    cfg.Webhooks.MaxAttempts,
+			InitialBackoff: cfg.Webhooks.InitialBackoff,
+			MaxBackoff:     cfg.Webhooks.MaxBackoff,
+		},
+		cfg.Webhooks.Timeout,
+		logger,
+	)
+
+	bus := outbox.NewBus()
+	bus.Subscribe(webhookSvc.Publish)
+
+	sinks := []outboxService.Sink{bus}
+	for _, sink := range cfg.Outbox.Sinks {
+		switch sink {
+		case "log":
+			sinks = append(sinks, outbox.NewLogSink(logger))
+		case "http":
+			sinks = append(sinks, outbox.NewHTTPSink(cfg.Outbox.HTTPURL, cfg.Outbox.HTTPTimeout))
+		}
+	}
+	outboxSvc := outboxService.NewOutboxService(outboxRepository.NewPostgreSQLRepository(db), sinks, cfg.Outbox.HTTPTimeout, logger)
+
+	reads := database.NewReadRouter(db, replica, cfg.Database.Replica.MaxLag)
+	subscriptionRepository := repository.NewPostgreSQLRepository(db, reads)
+	subscriptionService := service.NewSubscriptionService(subscriptionRepository, database.NewTransactor(db), budgetSvc, subscriptionRepository, logger)
+
+	return services{
+		subscription: tracedSubscriptionService{subscriptionService},
+		budget:       budgetSvc,
+		webhook:      webhookSvc,
+		outbox:       outboxSvc,
+		reads:        reads,
+		apiKey:       apiKeyService.NewAPIKeyService(apiKeyRepository.NewPostgreSQLRepository(db), cfg.Auth.AdminKey),
+	}
+}
+
+// bootstrapMemoryServices keeps subscriptions in memory, for demos and for
+// exercising the API without a database. Nothing would dispatch events, so
+// none are recorded.
+func bootstrapMemoryServices(cfg *config.Config, logger *slog.Logger) services {
+	subscriptionRepository := repository.NewInMemoryRepository()
+	subscriptionService := service.NewSubscriptionService(subscriptionRepository, subscriptionRepository, noBudgets{}, noEvents{}, logger)
+
+	return services{
+		subscription: tracedSubscriptionService{subscriptionService},
+		apiKey:       apiKeyService.NewAPIKeyService(apiKeyRepository.NewInMemoryRepository(), cfg.Auth.AdminKey),
+	}
+}
+
+// databaseChecks make readiness depend on the database being reachable and at
+// the schema version this build migrated it to.
+func databaseChecks(db *sql.DB, version uint) []health.Check {
+	return []health.Check{
+		{Name: "database", Run: db.PingContext},
+		{Name: "migrations", Run: func(ctx context.Context) error {
+			return database.CheckMigrations(ctx, db, version)
+		}},
+	}
+}
+
+// bootstrapSQLiteServices keeps subscriptions in a single SQLite file, for
+// small installations without a PostgreSQL server. Nothing would dispatch
+// events, so none are recorded.
+func bootstrapSQLiteServices(db *sql.DB, cfg *config.Config, logger *slog.Logger) services {
+	subscriptionRepository := repository.NewSQLiteRepository(db)
+	subscriptionService := service.NewSubscriptionService(subscriptionRepository, database.NewTransactor(db), noBudgets{}, noEvents{}, logger)
+
+	return services{
+		subscription: tracedSubscriptionService{subscriptionService},
+		apiKey:       apiKeyService.NewAPIKeyService(apiKeyRepository.NewSQLiteRepository(db), cfg.Auth.AdminKey),
+	}
+}
+
+// subscriptionMetrics reports gauges over the subscriptions active at scrape
+// time, per tenant. Every scrape reads all active subscriptions.
+func subscriptionMetrics(subscriptionService tracedSubscriptionService, tenants []model.Tenant) metrics.Collector {
+	return metrics.CollectorFunc(func(ctx context.Context) ([]metrics.Family, error) {
+		var active, users, spend []metrics.Sample
+		for _, t := range tenants {
+			stats, err := subscriptionService.GetSubscriptionStats(tenant.WithTenant(ctx, t))
+			if err != nil {
+				return nil, fmt.Errorf("subscriptionService.GetSubscriptionStats: %w", err)
+			}
+
+			labels := []metrics.Label{{Name: "tenant", Value: t.ID}}
+			active = append(active, metrics.Sample{Labels: labels, Value: float64(stats.ActiveSubscriptions)})
+			users = append(users, metrics.Sample{Labels: labels, Value: float64(stats.ActiveUsers)})
+			spend = append(spend, metrics.Sample{Labels: labels, Value: float64(stats.MonthlySpend)})
+		}
+
+		return []metrics.Family{
+			{
+				Name: "subscriptions_active", Help: "Number of subscriptions active now.", Type: metrics.TypeGauge,
+				Samples: active,
+			},
+			{
+				Name: "subscriptions_active_users", Help: "Number of users paying for an active subscription.", Type: metrics.TypeGauge,
+				Samples: users,
+			},
+			{
+				Name: "subscriptions_monthly_recurring_spend", Help: "Sum of the monthly prices of active subscriptions, in the default currency of the tenant.", Type: metrics.TypeGauge,
+				Samples: spend,
+			},
+		}, nil
+	})
 }
 
-func bootstrapHandler(db *sql.DB, logger *slog.Logger) http.Handler {
-	subscriptionRepository := repository.NewPostgreSQLRepository(db)
-	subscriptionService := service.NewSubscriptionService(subscriptionRepository)
+func setupSpanExporter(cfg config.TracingConfig) (tracing.Exporter, error) {
+	if cfg.Exporter == "file" {
+		return tracing.OpenOTLPFileExporter(cfg.File, cfg.ServiceName)
+	}
+	return tracing.NewOTLPJSONExporter(os.Stdout, cfg.ServiceName), nil
+}
+
+// setupJWTVerifier binds tokens without a tenant_id claim to the only tenant,
+// and rejects them when there are several.
+func setupJWTVerifier(cfg config.JWTConfig, tenants *tenant.Directory) (*auth.JWTVerifier, error) {
+	var keys []auth.JWTKey
+	if cfg.Secret != "" {
+		keys = append(keys, auth.HMACKey(cfg.Secret))
+	}
+	if cfg.PublicKeyFile != "" {
+		key, err := auth.ReadRSAPublicKeyFile(cfg.PublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("auth.ReadRSAPublicKeyFile: %w", err)
+		}
+		keys = append(keys, key)
+	}
+	if cfg.JWKSFile != "" {
+		jwks, err := auth.ReadJWKSFile(cfg.JWKSFile)
+		if err != nil {
+			return nil, fmt.Errorf("auth.ReadJWKSFile: %w", err)
+		}
+		keys = append(keys, jwks...)
+	}
+	var defaultTenant string
+	if single, ok := tenants.Single(); ok {
+		defaultTenant = single.ID
+	}
+	return auth.NewJWTVerifier(keys, cfg.Issuer, cfg.Audience, defaultTenant), nil
+}
+
+// bearerAuthenticator passes JWTs of end users to jwt and every other token
+// to apiKeys.
+type bearerAuthenticator struct {
+	apiKeys middlewares.Authenticator
+	jwt     middlewares.Authenticator
+}
+
+func (a bearerAuthenticator) Authenticate(ctx context.Context, token string) (model.Identity, error) {
+	if auth.IsJWT(token) {
+		return a.jwt.Authenticate(ctx, token)
+	}
+	return a.apiKeys.Authenticate(ctx, token)
+}
+
+// bootstrapHandler builds the routes and middlewares. tracer may be nil, which
+// turns tracing off, and so may authenticator, which opens every route to
+// everyone. API requests act for the tenant named in tenantHeader or bound to
+// their credential.
+func bootstrapHandler(svc services, healthChecker *health.Checker, registry *metrics.Registry, tracer *tracing.Tracer, authenticator middlewares.Authenticator, tenants *tenant.Directory, tenantHeader string, logger *slog.Logger) http.Handler {
+	subscriptionService := svc.subscription
+	budgetSvc := svc.budget
+	webhookSvc := svc.webhook
 
 	mx := http.NewServeMux()
 
-	mx.Handle("POST /api/subscriptions", create_subscription_handler.NewCreateSubscriptionHandler(subscriptionService, logger))
-	mx.Handle("GET /api/subscriptions/{id}", get_subscription_handler.NewGetSubscriptionHandler(subscriptionService, logger))
-	mx.Handle("PUT /api/subscriptions/{id}", update_subscription_handler.NewUpdateSubscriptionHandler(subscriptionService, logger))
-	mx.Handle("DELETE /api/subscriptions/{id}", delete_subscription_handler.NewDeleteSubscriptionHandler(subscriptionService, logger))
-	mx.Handle("GET /api/subscriptions", list_subscriptions_handler.NewListSubscriptionsHandler(subscriptionService, logger))
-	mx.Handle("GET /api/subscriptions/cost", get_cost_handler.NewGetCostHandler(subscriptionService, logger))
+	// API routes need a valid credential, one of the roles accessPolicy
+	// lists for them and a tenant. Probes, metrics and the documentation stay
+	// open.
+	api := func(pattern string, h http.Handler) {
+		if _, ok := accessPolicy[pattern]; !ok {
+			panic("no access policy for " + pattern)
+		}
+		if authenticator != nil {
+			h = middlewares.NewAuthorizationMiddleware(h, accessPolicy, logger)
+		}
+		h = middlewares.NewTenantMiddleware(h, tenants, tenantHeader, logger)
+		if authenticator != nil {
+			h = middlewares.NewAuthMiddleware(h, authenticator, logger)
+		}
+		mx.Handle(pattern, h)
+	}
+
+	api("POST /api/subscriptions", create_subscription_handler.NewCreateSubscriptionHandler(subscriptionService, logger))
+	api("POST /api/subscriptions:batch", batch_subscriptions_handler.NewBatchSubscriptionsHandler(subscriptionService, logger))
+	api("POST /api/subscriptions/import", import_subscriptions_handler.NewImportSubscriptionsHandler(subscriptionService, logger))
+	api("GET /api/subscriptions/{id}", get_subscription_handler.NewGetSubscriptionHandler(subscriptionService, logger))
+	api("PUT /api/subscriptions/{id}", update_subscription_handler.NewUpdateSubscriptionHandler(subscriptionService, logger))
+	api("DELETE /api/subscriptions/{id}", delete_subscription_handler.NewDeleteSubscriptionHandler(subscriptionService, logger))
+	api("GET /api/subscriptions/{id}/members", get_subscription_members_handler.NewGetSubscriptionMembersHandler(subscriptionService, logger))
+	api("PUT /api/subscriptions/{id}/members", update_subscription_members_handler.NewUpdateSubscriptionMembersHandler(subscriptionService, logger))
+	api("GET /api/subscriptions", list_subscriptions_handler.NewListSubscriptionsHandler(subscriptionService, logger))
+	api("GET /api/subscriptions/export", export_subscriptions_handler.NewExportSubscriptionsHandler(subscriptionService, logger))
+	api("GET /api/subscriptions/cost", get_cost_handler.NewGetCostHandler(subscriptionService, logger))
+	api("GET /api/subscriptions/duplicates", find_duplicates_handler.NewFindDuplicatesHandler(subscriptionService, logger))
+	api("GET /api/subscriptions/anomalies", find_price_anomalies_handler.NewFindPriceAnomaliesHandler(subscriptionService, logger))
+
+	api("GET /api/users/{user_id}/renewals.ics", get_renewals_calendar_handler.NewGetRenewalsCalendarHandler(subscriptionService, logger))
+	api("POST /api/users/{id}/subscription-suggestions", suggest_subscriptions_handler.NewSuggestSubscriptionsHandler(subscriptionService, logger))
+
+	if budgetSvc != nil {
+		api("POST /api/budgets", create_budget_handler.NewCreateBudgetHandler(budgetSvc, logger))
+		api("GET /api/budgets/{id}", get_budget_handler.NewGetBudgetHandler(budgetSvc, logger))
+		api("PUT /api/budgets/{id}", update_budget_handler.NewUpdateBudgetHandler(budgetSvc, logger))
+		api("DELETE /api/budgets/{id}", delete_budget_handler.NewDeleteBudgetHandler(budgetSvc, logger))
+		api("GET /api/users/{id}/budgets", list_budgets_handler.NewListBudgetsHandler(budgetSvc, logger))
+		api("GET /api/users/{id}/alerts", list_alerts_handler.NewListAlertsHandler(budgetSvc, logger))
+	}
+
+	if webhookSvc != nil {
+		api("POST /api/webhooks", create_webhook_handler.NewCreateWebhookHandler(webhookSvc, logger))
+		api("GET /api/webhooks", list_webhooks_handler.NewListWebhooksHandler(webhookSvc, logger))
+		api("GET /api/webhooks/{id}", get_webhook_handler.NewGetWebhookHandler(webhookSvc, logger))
+		api("DELETE /api/webhooks/{id}", delete_webhook_handler.NewDeleteWebhookHandler(webhookSvc, logger))
+		api("GET /api/webhooks/{id}/deliveries", list_webhook_deliveries_handler.NewListWebhookDeliveriesHandler(webhookSvc, logger))
+	}
+
+	api("POST /api/api-keys", issue_api_key_handler.NewIssueAPIKeyHandler(svc.apiKey, logger))
+	api("GET /api/api-keys", list_api_keys_handler.NewListAPIKeysHandler(svc.apiKey, logger))
+	api("DELETE /api/api-keys/{id}", revoke_api_key_handler.NewRevokeAPIKeyHandler(svc.apiKey, logger))
+
+	// Probes and metrics
+	mx.Handle("GET /healthz", liveness_handler.NewLivenessHandler(logger))
+	mx.Handle("GET /readyz", readiness_handler.NewReadinessHandler(healthChecker, logger))
+	mx.Handle("GET /metrics", metrics_handler.NewMetricsHandler(registry, logger))
 
 	// Swagger
 	mx.Handle("GET /swagger/", httpSwagger.WrapHandler)
 
-	middleware := middlewares.NewTimerMiddleware(mx)
+	middleware := middlewares.NewRecoveryMiddleware(mx, logger)
+	middleware = middlewares.NewTimerMiddleware(middleware, registry)
+	if tracer != nil {
+		middleware = middlewares.NewTracingMiddleware(middleware, tracer)
+	}
+	middleware = middlewares.NewAccessLogMiddleware(middleware, logger, "GET /healthz", "GET /readyz", "GET /metrics")
 
 	return middleware
 }
@@ -0,0 +1,171 @@
+package batch_subscriptions_handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/infra/http/problem"
+	"github.com/golangtestcases/subscribe-service/internal/infra/logging"
+	"github.com/google/uuid"
+)
+
+// maxOperations limits the size of one batch so that a single transaction
+// stays reasonably short.
+const maxOperations = 1000
+
+type SubscriptionService interface {
+	ExecuteBatch(ctx context.Context, operations []model.BatchOperation, atomic bool) ([]model.BatchResult, error)
+}
+
+type BatchSubscriptionsHandler struct {
+	subscriptionService SubscriptionService
+	logger              *slog.Logger
+}
+
+func NewBatchSubscriptionsHandler(subscriptionService SubscriptionService, logger *slog.Logger) *BatchSubscriptionsHandler {
+	return &BatchSubscriptionsHandler{
+		subscriptionService: subscriptionService,
+		logger:              logger,
+	}
+}
+
+// @Summary Batch create, update and delete subscriptions
+// @Description Run up to 1000 operations in one transaction. In atomic mode (default) any failure rolls back the whole batch and the response status is 422; in best_effort mode failed operations are skipped and the rest is committed.
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param batch body BatchSubscriptionsRequest true "Operations"
+// @Success 200 {object} BatchSubscriptionsResponse
+// @Failure 400 {object} problem.Details
+// @Failure 422 {object} BatchSubscriptionsResponse
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
+// @Router /api/subscriptions:batch [post]
+func (h *BatchSubscriptionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context(), h.logger)
+
+	defer r.Body.Close()
+
+	var req BatchSubscriptionsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error("failed to decode request", "error", err)
+		problem.Write(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if req.Mode == "" {
+		req.Mode = modeAtomic
+	}
+	if req.Mode != modeAtomic && req.Mode != modeBestEffort {
+		problem.Write(w, r, http.StatusBadRequest, "invalid mode, expected atomic or best_effort")
+		return
+	}
+	if len(req.Operations) == 0 {
+		problem.Write(w, r, http.StatusBadRequest, "operations are required")
+		return
+	}
+	if len(req.Operations) > maxOperations {
+		problem.Write(w, r, http.StatusBadRequest, fmt.Sprintf("too many operations, at most %d allowed", maxOperations))
+		return
+	}
+
+	operations := make([]model.BatchOperation, 0, len(req.Operations))
+	for i, operationReq := range req.Operations {
+		operation, err := operationReq.ToModel()
+		if err != nil {
+			logger.Error("failed to convert request to model", "index", i, "error", err)
+			problem.Write(w, r, http.StatusBadRequest, fmt.Sprintf("operations[%d]: %s", i, err))
+			return
+		}
+		operations = append(operations, operation)
+	}
+
+	results, err := h.subscriptionService.ExecuteBatch(r.Context(), operations, req.Mode == modeAtomic)
+	if err != nil && !errors.Is(err, model.ErrBatchRolledBack) {
+		logger.Error("failed to execute batch", "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, "")
+		return
+	}
+
+	response := BatchSubscriptionsResponse{
+		Mode:      req.Mode,
+		Committed: err == nil,
+		Results:   make([]ItemResult, 0, len(results)),
+	}
+	for i, result := range results {
+		item := ItemResult{
+			Index:  i,
+			Op:     string(result.Type),
+			Status: string(result.Status),
+		}
+		if result.Err != nil {
+			item.Error = itemError(result.Err)
+			if item.Error == internalError {
+				logger.Error("batch operation failed", "index", i, "op", result.Type, "error", result.Err)
+			}
+		}
+		if result.Subscription.ID != uuid.Nil {
+			item.Subscription = toSubscriptionItem(result.Subscription)
+		}
+		response.Results = append(response.Results, item)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if !response.Committed {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Error("failed to encode response", "error", err)
+	}
+}
+
+const internalError = "internal error"
+
+// itemError returns the message reported for a failed operation. Only
+// validation and access errors are meant for the client; other errors may
+// carry database details and are reported as internalError.
+func itemError(err error) string {
+	var validationErr *model.ValidationError
+	if errors.As(err, &validationErr) {
+		return validationErr.Message
+	}
+	var forbiddenErr *model.ForbiddenError
+	if errors.As(err, &forbiddenErr) {
+		return forbiddenErr.Message
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return "subscription not found"
+	}
+	return internalError
+}
+
+func toSubscriptionItem(subscription model.Subscription) *SubscriptionItem {
+	item := &SubscriptionItem{
+		ID:          subscription.ID.String(),
+		ServiceName: subscription.ServiceName,
+		Price:       subscription.Price,
+	}
+	if subscription.UserID != uuid.Nil {
+		item.UserID = subscription.UserID.String()
+	}
+	if !subscription.StartDate.IsZero() {
+		item.StartDate = subscription.StartDate.Format("01-2006")
+	}
+	item.EndDate = formatEndDate(subscription.EndDate)
+	return item
+}
+
+func formatEndDate(endDate *time.Time) *string {
+	if endDate == nil {
+		return nil
+	}
+	formatted := endDate.Format("01-2006")
+	return &formatted
+}
@@ -0,0 +1,93 @@
+package batch_subscriptions_handler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+const (
+	modeAtomic     = "atomic"
+	modeBestEffort = "best_effort"
+)
+
+type BatchSubscriptionsRequest struct {
+	Mode       string             `json:"mode" enums:"atomic,best_effort"`
+	Operations []OperationRequest `json:"operations"`
+}
+
+type OperationRequest struct {
+	Op           string               `json:"op" enums:"create,update,delete"`
+	ID           string               `json:"id,omitempty"`
+	Subscription *SubscriptionRequest `json:"subscription,omitempty"`
+}
+
+type SubscriptionRequest struct {
+	ServiceName string  `json:"service_name"`
+	Price       int     `json:"price"`
+	UserID      string  `json:"user_id"`
+	StartDate   string  `json:"start_date"`
+	EndDate     *string `json:"end_date,omitempty"`
+}
+
+func (r *OperationRequest) ToModel() (model.BatchOperation, error) {
+	operation := model.BatchOperation{Type: model.BatchOperationType(r.Op)}
+
+	switch operation.Type {
+	case model.BatchOperationCreate, model.BatchOperationUpdate, model.BatchOperationDelete:
+	default:
+		return model.BatchOperation{}, fmt.Errorf("unknown op %q, expected create, update or delete", r.Op)
+	}
+
+	if operation.Type != model.BatchOperationCreate {
+		id, err := uuid.Parse(r.ID)
+		if err != nil {
+			return model.BatchOperation{}, fmt.Errorf("invalid id format: %w", err)
+		}
+		operation.ID = id
+	}
+
+	if operation.Type != model.BatchOperationDelete {
+		if r.Subscription == nil {
+			return model.BatchOperation{}, fmt.Errorf("subscription is required for %s", r.Op)
+		}
+		subscription, err := r.Subscription.ToModel()
+		if err != nil {
+			return model.BatchOperation{}, err
+		}
+		operation.Subscription = subscription
+	}
+
+	return operation, nil
+}
+
+func (r *SubscriptionRequest) ToModel() (model.Subscription, error) {
+	userID, err := uuid.Parse(r.UserID)
+	if err != nil {
+		return model.Subscription{}, fmt.Errorf("invalid user_id format: %w", err)
+	}
+
+	startDate, err := time.Parse("01-2006", r.StartDate)
+	if err != nil {
+		return model.Subscription{}, fmt.Errorf("invalid start_date format, expected MM-YYYY: %w", err)
+	}
+
+	var endDate *time.Time
+	if r.EndDate != nil {
+		parsed, err := time.Parse("01-2006", *r.EndDate)
+		if err != nil {
+			return model.Subscription{}, fmt.Errorf("invalid end_date format, expected MM-YYYY: %w", err)
+		}
+		endDate = &parsed
+	}
+
+	return model.Subscription{
+		ServiceName: r.ServiceName,
+		Price:       r.Price,
+		UserID:      userID,
+		StartDate:   startDate,
+		EndDate:     endDate,
+	}, nil
+}
@@ -0,0 +1,24 @@
+package batch_subscriptions_handler
+
+type BatchSubscriptionsResponse struct {
+	Mode      string       `json:"mode"`
+	Committed bool         `json:"committed"`
+	Results   []ItemResult `json:"results"`
+}
+
+type ItemResult struct {
+	Index        int               `json:"index"`
+	Op           string            `json:"op"`
+	Status       string            `json:"status" enums:"succeeded,failed,rolled_back,skipped"`
+	Error        string            `json:"error,omitempty"`
+	Subscription *SubscriptionItem `json:"subscription,omitempty"`
+}
+
+type SubscriptionItem struct {
+	ID          string  `json:"id"`
+	ServiceName string  `json:"service_name,omitempty"`
+	Price       int     `json:"price,omitempty"`
+	UserID      string  `json:"user_id,omitempty"`
+	StartDate   string  `json:"start_date,omitempty"`
+	EndDate     *string `json:"end_date,omitempty"`
+}
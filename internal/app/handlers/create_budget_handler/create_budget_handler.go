@@ -0,0 +1,93 @@
+package create_budget_handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/infra/http/problem"
+	"github.com/golangtestcases/subscribe-service/internal/infra/logging"
+)
+
+type BudgetService interface {
+	CreateBudget(ctx context.Context, budget model.Budget) (model.Budget, error)
+}
+
+type CreateBudgetHandler struct {
+	budgetService BudgetService
+	logger        *slog.Logger
+}
+
+func NewCreateBudgetHandler(budgetService BudgetService, logger *slog.Logger) *CreateBudgetHandler {
+	return &CreateBudgetHandler{
+		budgetService: budgetService,
+		logger:        logger,
+	}
+}
+
+// @Summary Create budget
+// @Description Create a monthly budget, overall or for a single service
+// @Tags budgets
+// @Accept json
+// @Produce json
+// @Param budget body CreateBudgetRequest true "Budget data"
+// @Success 201 {object} CreateBudgetResponse
+// @Failure 400 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
+// @Router /api/budgets [post]
+func (h *CreateBudgetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context(), h.logger)
+
+	defer r.Body.Close()
+
+	var req CreateBudgetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error("failed to decode request", "error", err)
+		problem.Write(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	budget, err := req.ToModel()
+	if err != nil {
+		logger.Error("failed to convert request to model", "error", err)
+		problem.Write(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	newBudget, err := h.budgetService.CreateBudget(r.Context(), budget)
+	if err != nil {
+		var validationErr *model.ValidationError
+		if errors.As(err, &validationErr) {
+			logger.Info("invalid budget", "error", err)
+			problem.Write(w, r, http.StatusBadRequest, validationErr.Message)
+			return
+		}
+		var forbiddenErr *model.ForbiddenError
+		if errors.As(err, &forbiddenErr) {
+			logger.Info("access denied", "error", err)
+			problem.Write(w, r, http.StatusForbidden, forbiddenErr.Message)
+			return
+		}
+		logger.Error("failed to create budget", "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, "")
+		return
+	}
+
+	response := CreateBudgetResponse{
+		ID:          newBudget.ID.String(),
+		UserID:      newBudget.UserID.String(),
+		ServiceName: newBudget.ServiceName,
+		Amount:      newBudget.Amount,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Error("failed to encode response", "error", err)
+	}
+}
@@ -0,0 +1,27 @@
+package create_budget_handler
+
+import (
+	"fmt"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+type CreateBudgetRequest struct {
+	UserID      string  `json:"user_id"`
+	ServiceName *string `json:"service_name,omitempty"`
+	Amount      int     `json:"amount"`
+}
+
+func (r *CreateBudgetRequest) ToModel() (model.Budget, error) {
+	userID, err := uuid.Parse(r.UserID)
+	if err != nil {
+		return model.Budget{}, fmt.Errorf("invalid user_id format: %w", err)
+	}
+
+	return model.Budget{
+		UserID:      userID,
+		ServiceName: r.ServiceName,
+		Amount:      r.Amount,
+	}, nil
+}
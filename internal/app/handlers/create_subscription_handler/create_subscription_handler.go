@@ -3,15 +3,20 @@ package create_subscription_handler
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"time"
 
 	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/infra/http/problem"
+	"github.com/golangtestcases/subscribe-service/internal/infra/logging"
 )
 
 type SubscriptionService interface {
 	CreateSubscription(ctx context.Context, subscription model.Subscription) (model.Subscription, error)
+	FindDuplicatesOf(ctx context.Context, subscription model.Subscription) ([]model.Subscription, error)
 }
 
 type CreateSubscriptionHandler struct {
@@ -33,30 +38,46 @@ func NewCreateSubscriptionHandler(subscriptionService SubscriptionService, logge
 // @Produce json
 // @Param subscription body CreateSubscriptionRequest true "Subscription data"
 // @Success 201 {object} CreateSubscriptionResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Failure 400 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
 // @Router /api/subscriptions [post]
 func (h *CreateSubscriptionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context(), h.logger)
+
 	defer r.Body.Close()
 
 	var req CreateSubscriptionRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.logger.Error("failed to decode request", "error", err)
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+		logger.Error("failed to decode request", "error", err)
+		problem.Write(w, r, http.StatusBadRequest, "invalid request body")
 		return
 	}
 
 	subscription, err := req.ToModel()
 	if err != nil {
-		h.logger.Error("failed to convert request to model", "error", err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		logger.Error("failed to convert request to model", "error", err)
+		problem.Write(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	newSubscription, err := h.subscriptionService.CreateSubscription(r.Context(), subscription)
 	if err != nil {
-		h.logger.Error("failed to create subscription", "error", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		var validationErr *model.ValidationError
+		if errors.As(err, &validationErr) {
+			logger.Info("invalid subscription", "error", err)
+			problem.Write(w, r, http.StatusBadRequest, validationErr.Message)
+			return
+		}
+		var forbiddenErr *model.ForbiddenError
+		if errors.As(err, &forbiddenErr) {
+			logger.Info("access denied", "error", err)
+			problem.Write(w, r, http.StatusForbidden, forbiddenErr.Message)
+			return
+		}
+		logger.Error("failed to create subscription", "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, "")
 		return
 	}
 
@@ -69,10 +90,20 @@ func (h *CreateSubscriptionHandler) ServeHTTP(w http.ResponseWriter, r *http.Req
 		EndDate:     formatEndDate(newSubscription.EndDate),
 	}
 
+	duplicates, err := h.subscriptionService.FindDuplicatesOf(r.Context(), newSubscription)
+	if err != nil {
+		logger.Error("failed to find duplicate subscriptions", "id", newSubscription.ID, "error", err)
+	}
+	for _, duplicate := range duplicates {
+		response.Warnings = append(response.Warnings, fmt.Sprintf(
+			"duplicates existing subscription %s (%s)", duplicate.ID, duplicate.ServiceName,
+		))
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	if err := json.NewEncoder(w).Encode(response); err != nil {
-		h.logger.Error("failed to encode response", "error", err)
+		logger.Error("failed to encode response", "error", err)
 	}
 }
 
@@ -1,14 +1,11 @@
 package create_subscription_handler
 
 type CreateSubscriptionResponse struct {
-	ID          string  `json:"id"`
-	ServiceName string  `json:"service_name"`
-	Price       int     `json:"price"`
-	UserID      string  `json:"user_id"`
-	StartDate   string  `json:"start_date"`
-	EndDate     *string `json:"end_date,omitempty"`
-}
-
-type ErrorResponse struct {
-	Error string `json:"error"`
+	ID          string   `json:"id"`
+	ServiceName string   `json:"service_name"`
+	Price       int      `json:"price"`
+	UserID      string   `json:"user_id"`
+	StartDate   string   `json:"start_date"`
+	EndDate     *string  `json:"end_date,omitempty"`
+	Warnings    []string `json:"warnings,omitempty"`
 }
@@ -0,0 +1,79 @@
+package create_webhook_handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/infra/http/problem"
+	"github.com/golangtestcases/subscribe-service/internal/infra/logging"
+)
+
+type WebhookService interface {
+	CreateWebhook(ctx context.Context, webhook model.Webhook) (model.Webhook, error)
+}
+
+type CreateWebhookHandler struct {
+	webhookService WebhookService
+	logger         *slog.Logger
+}
+
+func NewCreateWebhookHandler(webhookService WebhookService, logger *slog.Logger) *CreateWebhookHandler {
+	return &CreateWebhookHandler{
+		webhookService: webhookService,
+		logger:         logger,
+	}
+}
+
+// @Summary Create webhook
+// @Description Register a URL to be notified of subscription events. The secret signs every delivery; when omitted one is generated and returned only in this response
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param webhook body CreateWebhookRequest true "Webhook data"
+// @Success 201 {object} CreateWebhookResponse
+// @Failure 400 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
+// @Router /api/webhooks [post]
+func (h *CreateWebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context(), h.logger)
+
+	defer r.Body.Close()
+
+	var req CreateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error("failed to decode request", "error", err)
+		problem.Write(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	newWebhook, err := h.webhookService.CreateWebhook(r.Context(), req.ToModel())
+	if err != nil {
+		var validationErr *model.ValidationError
+		if errors.As(err, &validationErr) {
+			logger.Info("invalid webhook", "error", err)
+			problem.Write(w, r, http.StatusBadRequest, validationErr.Message)
+			return
+		}
+		logger.Error("failed to create webhook", "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, "")
+		return
+	}
+
+	response := CreateWebhookResponse{
+		ID:         newWebhook.ID.String(),
+		URL:        newWebhook.URL,
+		Secret:     newWebhook.Secret,
+		EventTypes: newWebhook.EventTypes,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Error("failed to encode response", "error", err)
+	}
+}
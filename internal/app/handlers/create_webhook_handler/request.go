@@ -0,0 +1,17 @@
+package create_webhook_handler
+
+import "github.com/golangtestcases/subscribe-service/internal/domain/model"
+
+type CreateWebhookRequest struct {
+	URL        string            `json:"url"`
+	Secret     string            `json:"secret,omitempty"`
+	EventTypes []model.EventType `json:"event_types"`
+}
+
+func (r *CreateWebhookRequest) ToModel() model.Webhook {
+	return model.Webhook{
+		URL:        r.URL,
+		Secret:     r.Secret,
+		EventTypes: r.EventTypes,
+	}
+}
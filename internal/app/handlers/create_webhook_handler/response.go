@@ -0,0 +1,10 @@
+package create_webhook_handler
+
+import "github.com/golangtestcases/subscribe-service/internal/domain/model"
+
+type CreateWebhookResponse struct {
+	ID         string            `json:"id"`
+	URL        string            `json:"url"`
+	Secret     string            `json:"secret"`
+	EventTypes []model.EventType `json:"event_types"`
+}
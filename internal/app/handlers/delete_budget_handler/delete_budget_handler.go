@@ -0,0 +1,72 @@
+package delete_budget_handler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/infra/http/problem"
+	"github.com/golangtestcases/subscribe-service/internal/infra/logging"
+	"github.com/google/uuid"
+)
+
+type BudgetService interface {
+	DeleteBudget(ctx context.Context, id uuid.UUID) error
+}
+
+type DeleteBudgetHandler struct {
+	budgetService BudgetService
+	logger        *slog.Logger
+}
+
+func NewDeleteBudgetHandler(budgetService BudgetService, logger *slog.Logger) *DeleteBudgetHandler {
+	return &DeleteBudgetHandler{
+		budgetService: budgetService,
+		logger:        logger,
+	}
+}
+
+// @Summary Delete budget
+// @Description Delete budget by ID
+// @Tags budgets
+// @Param id path string true "Budget ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
+// @Router /api/budgets/{id} [delete]
+func (h *DeleteBudgetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context(), h.logger)
+
+	idStr := r.PathValue("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.Error("invalid budget id", "id", idStr, "error", err)
+		problem.Write(w, r, http.StatusBadRequest, "invalid budget id")
+		return
+	}
+
+	err = h.budgetService.DeleteBudget(r.Context(), id)
+	if err != nil {
+		var validationErr *model.ValidationError
+		if errors.As(err, &validationErr) {
+			logger.Info("invalid request", "error", err)
+			problem.Write(w, r, http.StatusBadRequest, validationErr.Message)
+			return
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			logger.Info("budget not found", "id", id)
+			problem.Write(w, r, http.StatusNotFound, "budget not found")
+			return
+		}
+		logger.Error("failed to delete budget", "id", id, "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, "")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -7,6 +7,9 @@ import (
 	"log/slog"
 	"net/http"
 
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/infra/http/problem"
+	"github.com/golangtestcases/subscribe-service/internal/infra/logging"
 	"github.com/google/uuid"
 )
 
@@ -31,28 +34,37 @@ func NewDeleteSubscriptionHandler(subscriptionService SubscriptionService, logge
 // @Tags subscriptions
 // @Param id path string true "Subscription ID"
 // @Success 204 "No Content"
-// @Failure 400 {object} ErrorResponse
-// @Failure 404 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Failure 400 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
 // @Router /api/subscriptions/{id} [delete]
 func (h *DeleteSubscriptionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context(), h.logger)
+
 	idStr := r.PathValue("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		h.logger.Error("invalid subscription id", "id", idStr, "error", err)
-		http.Error(w, "invalid subscription id", http.StatusBadRequest)
+		logger.Error("invalid subscription id", "id", idStr, "error", err)
+		problem.Write(w, r, http.StatusBadRequest, "invalid subscription id")
 		return
 	}
 
 	err = h.subscriptionService.DeleteSubscription(r.Context(), id)
 	if err != nil {
+		var validationErr *model.ValidationError
+		if errors.As(err, &validationErr) {
+			logger.Info("invalid request", "error", err)
+			problem.Write(w, r, http.StatusBadRequest, validationErr.Message)
+			return
+		}
 		if errors.Is(err, sql.ErrNoRows) {
-			h.logger.Info("subscription not found", "id", id)
-			http.Error(w, "subscription not found", http.StatusNotFound)
+			logger.Info("subscription not found", "id", id)
+			problem.Write(w, r, http.StatusNotFound, "subscription not found")
 			return
 		}
-		h.logger.Error("failed to delete subscription", "id", id, "error", err)
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+		logger.Error("failed to delete subscription", "id", id, "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, "")
 		return
 	}
 
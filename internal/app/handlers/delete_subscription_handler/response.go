@@ -1,5 +0,0 @@
-package delete_subscription_handler
-
-type ErrorResponse struct {
-	Error string `json:"error"`
-}
\ No newline at end of file
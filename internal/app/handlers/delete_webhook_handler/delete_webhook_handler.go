@@ -0,0 +1,72 @@
+package delete_webhook_handler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/infra/http/problem"
+	"github.com/golangtestcases/subscribe-service/internal/infra/logging"
+	"github.com/google/uuid"
+)
+
+type WebhookService interface {
+	DeleteWebhook(ctx context.Context, id uuid.UUID) error
+}
+
+type DeleteWebhookHandler struct {
+	webhookService WebhookService
+	logger         *slog.Logger
+}
+
+func NewDeleteWebhookHandler(webhookService WebhookService, logger *slog.Logger) *DeleteWebhookHandler {
+	return &DeleteWebhookHandler{
+		webhookService: webhookService,
+		logger:         logger,
+	}
+}
+
+// @Summary Delete webhook
+// @Description Delete webhook by ID together with its delivery log
+// @Tags webhooks
+// @Param id path string true "Webhook ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
+// @Router /api/webhooks/{id} [delete]
+func (h *DeleteWebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context(), h.logger)
+
+	idStr := r.PathValue("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.Error("invalid webhook id", "id", idStr, "error", err)
+		problem.Write(w, r, http.StatusBadRequest, "invalid webhook id")
+		return
+	}
+
+	err = h.webhookService.DeleteWebhook(r.Context(), id)
+	if err != nil {
+		var validationErr *model.ValidationError
+		if errors.As(err, &validationErr) {
+			logger.Info("invalid request", "error", err)
+			problem.Write(w, r, http.StatusBadRequest, validationErr.Message)
+			return
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			logger.Info("webhook not found", "id", id)
+			problem.Write(w, r, http.StatusNotFound, "webhook not found")
+			return
+		}
+		logger.Error("failed to delete webhook", "id", id, "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, "")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,144 @@
+package export_subscriptions_handler
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/infra/http/problem"
+	"github.com/golangtestcases/subscribe-service/internal/infra/logging"
+)
+
+type SubscriptionService interface {
+	ExportSubscriptions(ctx context.Context, limit, offset int, fn func(model.Subscription) error) error
+}
+
+type ExportSubscriptionsHandler struct {
+	subscriptionService SubscriptionService
+	logger              *slog.Logger
+}
+
+func NewExportSubscriptionsHandler(subscriptionService SubscriptionService, logger *slog.Logger) *ExportSubscriptionsHandler {
+	return &ExportSubscriptionsHandler{
+		subscriptionService: subscriptionService,
+		logger:              logger,
+	}
+}
+
+// @Summary Export subscriptions
+// @Description Stream subscriptions as CSV, JSON Lines or XLSX. Accepts the same pagination as the list endpoint; without limit all subscriptions are exported. A token restricted to one user exports only that user's subscriptions, and CSV cells that would start a formula are prefixed with an apostrophe.
+// @Tags subscriptions
+// @Produce text/csv,application/jsonl,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "Export format" Enums(csv, jsonl, xlsx) default(csv)
+// @Param limit query int false "Limit"
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {file} file
+// @Failure 400 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
+// @Router /api/subscriptions/export [get]
+func (h *ExportSubscriptionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context(), h.logger)
+
+	formatName := r.URL.Query().Get("format")
+	if formatName == "" {
+		formatName = "csv"
+	}
+	exportFormat, ok := formats[formatName]
+	if !ok {
+		problem.Write(w, r, http.StatusBadRequest, "invalid format, expected csv, jsonl or xlsx")
+		return
+	}
+
+	limit := 0
+	offset := 0
+
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			limit = l
+		}
+	}
+
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
+			offset = o
+		}
+	}
+
+	// Output is buffered so that an error before the first flush can still be
+	// reported with a proper status code.
+	tw := &trackingWriter{w: w}
+	out := bufio.NewWriterSize(tw, 32<<10)
+
+	w.Header().Set("Content-Type", exportFormat.contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(
+		`attachment; filename="subscriptions-%s.%s"`, time.Now().Format("2006-01-02"), exportFormat.extension,
+	))
+
+	writer, err := exportFormat.newWriter(out)
+	if err == nil {
+		err = h.subscriptionService.ExportSubscriptions(r.Context(), limit, offset, func(subscription model.Subscription) error {
+			return writer.WriteRow(toRow(subscription))
+		})
+	}
+	if err == nil {
+		err = writer.Close()
+	}
+	if err == nil {
+		err = out.Flush()
+	}
+
+	if err != nil {
+		var forbiddenErr *model.ForbiddenError
+		if errors.As(err, &forbiddenErr) && !tw.written {
+			logger.Info("access denied", "error", err)
+			w.Header().Del("Content-Disposition")
+			problem.Write(w, r, http.StatusForbidden, forbiddenErr.Message)
+			return
+		}
+		logger.Error("failed to export subscriptions", "format", formatName, "error", err)
+		if !tw.written {
+			w.Header().Del("Content-Disposition")
+			problem.Write(w, r, http.StatusInternalServerError, "")
+		}
+	}
+}
+
+// trackingWriter records whether anything has reached the client yet.
+type trackingWriter struct {
+	w       http.ResponseWriter
+	written bool
+}
+
+func (t *trackingWriter) Write(p []byte) (int, error) {
+	t.written = true
+	return t.w.Write(p)
+}
+
+func toRow(subscription model.Subscription) SubscriptionRow {
+	return SubscriptionRow{
+		ID:          subscription.ID.String(),
+		ServiceName: subscription.ServiceName,
+		Price:       subscription.Price,
+		UserID:      subscription.UserID.String(),
+		StartDate:   subscription.StartDate.Format("01-2006"),
+		EndDate:     formatEndDate(subscription.EndDate),
+		CreatedAt:   subscription.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   subscription.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func formatEndDate(endDate *time.Time) *string {
+	if endDate == nil {
+		return nil
+	}
+	formatted := endDate.Format("01-2006")
+	return &formatted
+}
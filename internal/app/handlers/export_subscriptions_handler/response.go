@@ -0,0 +1,14 @@
+package export_subscriptions_handler
+
+// SubscriptionRow is one exported subscription. It is written as a JSON object
+// per line in the jsonl format; csv and xlsx use the same fields as columns.
+type SubscriptionRow struct {
+	ID          string  `json:"id"`
+	ServiceName string  `json:"service_name"`
+	Price       int     `json:"price"`
+	UserID      string  `json:"user_id"`
+	StartDate   string  `json:"start_date"`
+	EndDate     *string `json:"end_date,omitempty"`
+	CreatedAt   string  `json:"created_at"`
+	UpdatedAt   string  `json:"updated_at"`
+}
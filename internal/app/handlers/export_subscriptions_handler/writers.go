@@ -0,0 +1,92 @@
+package export_subscriptions_handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var columns = []string{"id", "service_name", "price", "user_id", "start_date", "end_date", "created_at", "updated_at"}
+
+// rowWriter writes exported rows in one of the supported formats.
+type rowWriter interface {
+	WriteRow(row SubscriptionRow) error
+	Close() error
+}
+
+type format struct {
+	contentType string
+	extension   string
+	newWriter   func(w io.Writer) (rowWriter, error)
+}
+
+var formats = map[string]format{
+	"csv":   {contentType: "text/csv; charset=utf-8", extension: "csv", newWriter: newCSVWriter},
+	"jsonl": {contentType: "application/jsonl", extension: "jsonl", newWriter: newJSONLWriter},
+	"xlsx":  {contentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", extension: "xlsx", newWriter: newXLSXWriter},
+}
+
+func (row SubscriptionRow) values() []string {
+	endDate := ""
+	if row.EndDate != nil {
+		endDate = *row.EndDate
+	}
+	return []string{
+		row.ID, row.ServiceName, strconv.Itoa(row.Price), row.UserID,
+		row.StartDate, endDate, row.CreatedAt, row.UpdatedAt,
+	}
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) (rowWriter, error) {
+	writer := &csvWriter{w: csv.NewWriter(w)}
+	if err := writer.w.Write(columns); err != nil {
+		return nil, err
+	}
+	return writer, nil
+}
+
+func (c *csvWriter) WriteRow(row SubscriptionRow) error {
+	values := row.values()
+	for i, value := range values {
+		values[i] = escapeFormula(value)
+	}
+	return c.w.Write(values)
+}
+
+// escapeFormula prefixes a cell that spreadsheet applications would run as a
+// formula with an apostrophe, so that opening the export does not run code
+// a user put into a service name. XLSX cells are written as inline strings,
+// which are never evaluated.
+func escapeFormula(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+type jsonlWriter struct {
+	enc *json.Encoder
+}
+
+func newJSONLWriter(w io.Writer) (rowWriter, error) {
+	return &jsonlWriter{enc: json.NewEncoder(w)}, nil
+}
+
+func (j *jsonlWriter) WriteRow(row SubscriptionRow) error {
+	return j.enc.Encode(row)
+}
+
+func (j *jsonlWriter) Close() error {
+	return nil
+}
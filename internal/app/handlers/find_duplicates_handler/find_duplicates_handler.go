@@ -0,0 +1,108 @@
+package find_duplicates_handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/infra/http/problem"
+	"github.com/golangtestcases/subscribe-service/internal/infra/logging"
+	"github.com/google/uuid"
+)
+
+type SubscriptionService interface {
+	FindDuplicates(ctx context.Context, userIDs []uuid.UUID) ([]model.DuplicateGroup, error)
+}
+
+type FindDuplicatesHandler struct {
+	subscriptionService SubscriptionService
+	logger              *slog.Logger
+}
+
+func NewFindDuplicatesHandler(subscriptionService SubscriptionService, logger *slog.Logger) *FindDuplicatesHandler {
+	return &FindDuplicatesHandler{
+		subscriptionService: subscriptionService,
+		logger:              logger,
+	}
+}
+
+// @Summary Find duplicate subscriptions
+// @Description Find overlapping active subscriptions to the same service. Several user_id values are treated as one user group.
+// @Tags subscriptions
+// @Produce json
+// @Param user_id query []string false "User IDs" collectionFormat(multi)
+// @Success 200 {object} FindDuplicatesResponse
+// @Failure 400 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
+// @Router /api/subscriptions/duplicates [get]
+func (h *FindDuplicatesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context(), h.logger)
+
+	var userIDs []uuid.UUID
+	for _, userIDStr := range r.URL.Query()["user_id"] {
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			logger.Error("invalid user_id", "user_id", userIDStr, "error", err)
+			problem.Write(w, r, http.StatusBadRequest, "invalid user_id format")
+			return
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	groups, err := h.subscriptionService.FindDuplicates(r.Context(), userIDs)
+	if err != nil {
+		var forbiddenErr *model.ForbiddenError
+		if errors.As(err, &forbiddenErr) {
+			logger.Info("access denied", "error", err)
+			problem.Write(w, r, http.StatusForbidden, forbiddenErr.Message)
+			return
+		}
+		logger.Error("failed to find duplicate subscriptions", "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, "")
+		return
+	}
+
+	response := FindDuplicatesResponse{Groups: []DuplicateGroup{}}
+	for _, group := range groups {
+		item := DuplicateGroup{
+			ServiceName:  group.ServiceName,
+			MonthlySpend: group.MonthlySpend,
+			WastedSpend:  group.WastedSpend,
+		}
+		for _, userID := range group.UserIDs {
+			item.UserIDs = append(item.UserIDs, userID.String())
+		}
+		for _, subscription := range group.Subscriptions {
+			item.Subscriptions = append(item.Subscriptions, SubscriptionItem{
+				ID:          subscription.ID.String(),
+				ServiceName: subscription.ServiceName,
+				Price:       subscription.Price,
+				UserID:      subscription.UserID.String(),
+				StartDate:   subscription.StartDate.Format("01-2006"),
+				EndDate:     formatEndDate(subscription.EndDate),
+			})
+		}
+
+		response.Groups = append(response.Groups, item)
+		response.TotalWastedSpend += group.WastedSpend
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Error("failed to encode response", "error", err)
+	}
+}
+
+func formatEndDate(endDate *time.Time) *string {
+	if endDate == nil {
+		return nil
+	}
+	formatted := endDate.Format("01-2006")
+	return &formatted
+}
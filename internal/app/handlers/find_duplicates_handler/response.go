@@ -0,0 +1,23 @@
+package find_duplicates_handler
+
+type FindDuplicatesResponse struct {
+	Groups           []DuplicateGroup `json:"groups"`
+	TotalWastedSpend int              `json:"total_wasted_spend"`
+}
+
+type DuplicateGroup struct {
+	ServiceName   string             `json:"service_name"`
+	UserIDs       []string           `json:"user_ids"`
+	Subscriptions []SubscriptionItem `json:"subscriptions"`
+	MonthlySpend  int                `json:"monthly_spend"`
+	WastedSpend   int                `json:"wasted_spend"`
+}
+
+type SubscriptionItem struct {
+	ID          string  `json:"id"`
+	ServiceName string  `json:"service_name"`
+	Price       int     `json:"price"`
+	UserID      string  `json:"user_id"`
+	StartDate   string  `json:"start_date"`
+	EndDate     *string `json:"end_date,omitempty"`
+}
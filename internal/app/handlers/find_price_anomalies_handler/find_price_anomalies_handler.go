@@ -0,0 +1,134 @@
+package find_price_anomalies_handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/infra/http/problem"
+	"github.com/golangtestcases/subscribe-service/internal/infra/logging"
+)
+
+type SubscriptionService interface {
+	FindPriceAnomalies(ctx context.Context, filter model.AnomalyFilter) ([]model.PriceAnomaly, error)
+}
+
+type FindPriceAnomaliesHandler struct {
+	subscriptionService SubscriptionService
+	logger              *slog.Logger
+}
+
+func NewFindPriceAnomaliesHandler(subscriptionService SubscriptionService, logger *slog.Logger) *FindPriceAnomaliesHandler {
+	return &FindPriceAnomaliesHandler{
+		subscriptionService: subscriptionService,
+		logger:              logger,
+	}
+}
+
+// @Summary Find price anomalies
+// @Description List subscriptions whose price is far from the median price of the same service in the period
+// @Tags subscriptions
+// @Produce json
+// @Param period query string false "Period (MM-YYYY), current month by default"
+// @Param threshold query number false "Allowed relative deviation from the median" default(0.5)
+// @Param min_samples query int false "Minimum subscriptions per service" default(5)
+// @Success 200 {object} FindPriceAnomaliesResponse
+// @Failure 400 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
+// @Router /api/subscriptions/anomalies [get]
+func (h *FindPriceAnomaliesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context(), h.logger)
+
+	filter := model.AnomalyFilter{}
+
+	if periodStr := r.URL.Query().Get("period"); periodStr != "" {
+		period, err := time.Parse("01-2006", periodStr)
+		if err != nil {
+			logger.Error("invalid period", "period", periodStr, "error", err)
+			problem.Write(w, r, http.StatusBadRequest, "invalid period format, expected MM-YYYY")
+			return
+		}
+		filter.Period = period
+	}
+
+	if thresholdStr := r.URL.Query().Get("threshold"); thresholdStr != "" {
+		threshold, err := strconv.ParseFloat(thresholdStr, 64)
+		if err != nil || threshold <= 0 {
+			logger.Error("invalid threshold", "threshold", thresholdStr, "error", err)
+			problem.Write(w, r, http.StatusBadRequest, "invalid threshold, expected a positive number")
+			return
+		}
+		filter.Threshold = threshold
+	}
+
+	if minSamplesStr := r.URL.Query().Get("min_samples"); minSamplesStr != "" {
+		minSamples, err := strconv.Atoi(minSamplesStr)
+		if err != nil || minSamples <= 0 {
+			logger.Error("invalid min_samples", "min_samples", minSamplesStr, "error", err)
+			problem.Write(w, r, http.StatusBadRequest, "invalid min_samples, expected a positive integer")
+			return
+		}
+		filter.MinSamples = minSamples
+	}
+
+	anomalies, err := h.subscriptionService.FindPriceAnomalies(r.Context(), filter)
+	if err != nil {
+		var forbiddenErr *model.ForbiddenError
+		if errors.As(err, &forbiddenErr) {
+			logger.Info("access denied", "error", err)
+			problem.Write(w, r, http.StatusForbidden, forbiddenErr.Message)
+			return
+		}
+		logger.Error("failed to find price anomalies", "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, "")
+		return
+	}
+
+	period := filter.Period
+	if period.IsZero() {
+		now := time.Now().UTC()
+		period = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	response := FindPriceAnomaliesResponse{
+		Period: period.Format("01-2006"),
+		Items:  []AnomalyItem{},
+	}
+	for _, anomaly := range anomalies {
+		subscription := anomaly.Subscription
+		response.Items = append(response.Items, AnomalyItem{
+			ID:           subscription.ID.String(),
+			ServiceName:  subscription.ServiceName,
+			Price:        subscription.Price,
+			UserID:       subscription.UserID.String(),
+			StartDate:    subscription.StartDate.Format("01-2006"),
+			EndDate:      formatEndDate(subscription.EndDate),
+			TypicalPrice: anomaly.Statistics.Median,
+			MinPrice:     anomaly.Statistics.Min,
+			MaxPrice:     anomaly.Statistics.Max,
+			SampleSize:   anomaly.Statistics.Count,
+			Deviation:    math.Round(anomaly.Deviation*100) / 100,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Error("failed to encode response", "error", err)
+	}
+}
+
+func formatEndDate(endDate *time.Time) *string {
+	if endDate == nil {
+		return nil
+	}
+	formatted := endDate.Format("01-2006")
+	return &formatted
+}
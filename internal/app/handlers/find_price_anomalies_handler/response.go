@@ -0,0 +1,20 @@
+package find_price_anomalies_handler
+
+type FindPriceAnomaliesResponse struct {
+	Period string        `json:"period"`
+	Items  []AnomalyItem `json:"items"`
+}
+
+type AnomalyItem struct {
+	ID           string  `json:"id"`
+	ServiceName  string  `json:"service_name"`
+	Price        int     `json:"price"`
+	UserID       string  `json:"user_id"`
+	StartDate    string  `json:"start_date"`
+	EndDate      *string `json:"end_date,omitempty"`
+	TypicalPrice int     `json:"typical_price"`
+	MinPrice     int     `json:"min_price"`
+	MaxPrice     int     `json:"max_price"`
+	SampleSize   int     `json:"sample_size"`
+	Deviation    float64 `json:"deviation"`
+}
@@ -0,0 +1,84 @@
+package get_budget_handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/infra/http/problem"
+	"github.com/golangtestcases/subscribe-service/internal/infra/logging"
+	"github.com/google/uuid"
+)
+
+type BudgetService interface {
+	GetBudgetByID(ctx context.Context, id uuid.UUID) (model.Budget, error)
+}
+
+type GetBudgetHandler struct {
+	budgetService BudgetService
+	logger        *slog.Logger
+}
+
+func NewGetBudgetHandler(budgetService BudgetService, logger *slog.Logger) *GetBudgetHandler {
+	return &GetBudgetHandler{
+		budgetService: budgetService,
+		logger:        logger,
+	}
+}
+
+// @Summary Get budget
+// @Description Get budget by ID
+// @Tags budgets
+// @Produce json
+// @Param id path string true "Budget ID"
+// @Success 200 {object} GetBudgetResponse
+// @Failure 400 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
+// @Router /api/budgets/{id} [get]
+func (h *GetBudgetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context(), h.logger)
+
+	idStr := r.PathValue("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.Error("invalid budget id", "id", idStr, "error", err)
+		problem.Write(w, r, http.StatusBadRequest, "invalid budget id")
+		return
+	}
+
+	budget, err := h.budgetService.GetBudgetByID(r.Context(), id)
+	if err != nil {
+		var validationErr *model.ValidationError
+		if errors.As(err, &validationErr) {
+			logger.Info("invalid request", "error", err)
+			problem.Write(w, r, http.StatusBadRequest, validationErr.Message)
+			return
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			logger.Info("budget not found", "id", id)
+			problem.Write(w, r, http.StatusNotFound, "budget not found")
+			return
+		}
+		logger.Error("failed to get budget", "id", id, "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, "")
+		return
+	}
+
+	response := GetBudgetResponse{
+		ID:          budget.ID.String(),
+		UserID:      budget.UserID.String(),
+		ServiceName: budget.ServiceName,
+		Amount:      budget.Amount,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Error("failed to encode response", "error", err)
+	}
+}
@@ -0,0 +1,8 @@
+package get_budget_handler
+
+type GetBudgetResponse struct {
+	ID          string  `json:"id"`
+	UserID      string  `json:"user_id"`
+	ServiceName *string `json:"service_name,omitempty"`
+	Amount      int     `json:"amount"`
+}
@@ -3,11 +3,15 @@ package get_cost_handler
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
 	"time"
 
 	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/infra/http/problem"
+	"github.com/golangtestcases/subscribe-service/internal/infra/logging"
+	"github.com/golangtestcases/subscribe-service/internal/infra/tenant"
 	"github.com/google/uuid"
 )
 
@@ -36,17 +40,21 @@ func NewGetCostHandler(subscriptionService SubscriptionService, logger *slog.Log
 // @Param start_date query string false "Start date (MM-YYYY)"
 // @Param end_date query string false "End date (MM-YYYY)"
 // @Success 200 {object} GetCostResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Failure 400 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
 // @Router /api/subscriptions/cost [get]
 func (h *GetCostHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context(), h.logger)
+
 	filter := model.CostFilter{}
 
 	if userIDStr := r.URL.Query().Get("user_id"); userIDStr != "" {
 		userID, err := uuid.Parse(userIDStr)
 		if err != nil {
-			h.logger.Error("invalid user_id", "user_id", userIDStr, "error", err)
-			http.Error(w, "invalid user_id format", http.StatusBadRequest)
+			logger.Error("invalid user_id", "user_id", userIDStr, "error", err)
+			problem.Write(w, r, http.StatusBadRequest, "invalid user_id format")
 			return
 		}
 		filter.UserID = &userID
@@ -59,8 +67,8 @@ func (h *GetCostHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if startDateStr := r.URL.Query().Get("start_date"); startDateStr != "" {
 		startDate, err := time.Parse("01-2006", startDateStr)
 		if err != nil {
-			h.logger.Error("invalid start_date", "start_date", startDateStr, "error", err)
-			http.Error(w, "invalid start_date format, expected MM-YYYY", http.StatusBadRequest)
+			logger.Error("invalid start_date", "start_date", startDateStr, "error", err)
+			problem.Write(w, r, http.StatusBadRequest, "invalid start_date format, expected MM-YYYY")
 			return
 		}
 		filter.StartDate = &startDate
@@ -69,8 +77,8 @@ func (h *GetCostHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if endDateStr := r.URL.Query().Get("end_date"); endDateStr != "" {
 		endDate, err := time.Parse("01-2006", endDateStr)
 		if err != nil {
-			h.logger.Error("invalid end_date", "end_date", endDateStr, "error", err)
-			http.Error(w, "invalid end_date format, expected MM-YYYY", http.StatusBadRequest)
+			logger.Error("invalid end_date", "end_date", endDateStr, "error", err)
+			problem.Write(w, r, http.StatusBadRequest, "invalid end_date format, expected MM-YYYY")
 			return
 		}
 		filter.EndDate = &endDate
@@ -78,17 +86,25 @@ func (h *GetCostHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	totalCost, err := h.subscriptionService.GetTotalCost(r.Context(), filter)
 	if err != nil {
-		h.logger.Error("failed to get total cost", "error", err)
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+		var forbiddenErr *model.ForbiddenError
+		if errors.As(err, &forbiddenErr) {
+			logger.Info("access denied", "error", err)
+			problem.Write(w, r, http.StatusForbidden, forbiddenErr.Message)
+			return
+		}
+		logger.Error("failed to get total cost", "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, "")
 		return
 	}
 
+	t, _ := tenant.FromContext(r.Context())
 	response := GetCostResponse{
 		TotalCost: totalCost,
+		Currency:  t.DefaultCurrency,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(response); err != nil {
-		h.logger.Error("failed to encode response", "error", err)
+		logger.Error("failed to encode response", "error", err)
 	}
 }
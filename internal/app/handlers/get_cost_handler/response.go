@@ -1,9 +1,8 @@
 package get_cost_handler
 
+// GetCostResponse is the total cost in Currency, the default currency of the
+// tenant.
 type GetCostResponse struct {
-	TotalCost int `json:"total_cost"`
+	TotalCost int    `json:"total_cost"`
+	Currency  string `json:"currency"`
 }
-
-type ErrorResponse struct {
-	Error string `json:"error"`
-}
\ No newline at end of file
@@ -0,0 +1,82 @@
+package get_renewals_calendar_handler
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+)
+
+// calendarWriter writes an RFC 5545 calendar: CRLF line endings, content lines
+// folded at 75 octets and TEXT values escaped.
+type calendarWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (c *calendarWriter) line(name, value string) {
+	if c.err != nil {
+		return
+	}
+
+	content := name + ":" + value
+	for len(content) > 75 {
+		cut := 75
+		// Do not split a multi-byte UTF-8 sequence.
+		for cut > 0 && content[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		if _, c.err = io.WriteString(c.w, content[:cut]+"\r\n"); c.err != nil {
+			return
+		}
+		content = " " + content[cut:]
+	}
+	_, c.err = io.WriteString(c.w, content+"\r\n")
+}
+
+// writeRenewals writes one recurring VEVENT per subscription. Subscriptions are
+// billed monthly, so every event repeats monthly from the start date and stops
+// after the end date if there is one. Prices are in currency.
+func writeRenewals(w io.Writer, subscriptions []model.Subscription, currency string) error {
+	c := &calendarWriter{w: w}
+
+	c.line("BEGIN", "VCALENDAR")
+	c.line("VERSION", "2.0")
+	c.line("PRODID", "-//subscribe-service//renewals//EN")
+	c.line("CALSCALE", "GREGORIAN")
+	c.line("METHOD", "PUBLISH")
+	c.line("X-WR-CALNAME", escapeText("Subscription renewals"))
+
+	for _, subscription := range subscriptions {
+		rule := "FREQ=MONTHLY"
+		if subscription.EndDate != nil {
+			rule += ";UNTIL=" + subscription.EndDate.Format("20060102")
+		}
+
+		c.line("BEGIN", "VEVENT")
+		c.line("UID", subscription.ID.String()+"@subscribe-service")
+		c.line("DTSTAMP", calendarTimestamp(subscription.UpdatedAt))
+		c.line("DTSTART;VALUE=DATE", subscription.StartDate.Format("20060102"))
+		c.line("DTEND;VALUE=DATE", subscription.StartDate.AddDate(0, 0, 1).Format("20060102"))
+		c.line("RRULE", rule)
+		c.line("SUMMARY", escapeText(fmt.Sprintf("%s renewal", subscription.ServiceName)))
+		c.line("DESCRIPTION", escapeText(fmt.Sprintf("Price: %d %s", subscription.Price, currency)))
+		c.line("TRANSP", "TRANSPARENT")
+		c.line("END", "VEVENT")
+	}
+
+	c.line("END", "VCALENDAR")
+	return c.err
+}
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func escapeText(value string) string {
+	return textEscaper.Replace(value)
+}
+
+func calendarTimestamp(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
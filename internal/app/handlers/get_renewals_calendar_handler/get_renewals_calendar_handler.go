@@ -0,0 +1,87 @@
+package get_renewals_calendar_handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/infra/http/problem"
+	"github.com/golangtestcases/subscribe-service/internal/infra/logging"
+	"github.com/golangtestcases/subscribe-service/internal/infra/tenant"
+	"github.com/google/uuid"
+)
+
+type SubscriptionService interface {
+	ListActiveSubscriptions(ctx context.Context, userID uuid.UUID) ([]model.Subscription, error)
+}
+
+type GetRenewalsCalendarHandler struct {
+	subscriptionService SubscriptionService
+	logger              *slog.Logger
+}
+
+func NewGetRenewalsCalendarHandler(subscriptionService SubscriptionService, logger *slog.Logger) *GetRenewalsCalendarHandler {
+	return &GetRenewalsCalendarHandler{
+		subscriptionService: subscriptionService,
+		logger:              logger,
+	}
+}
+
+// @Summary Renewals calendar
+// @Description iCalendar (RFC 5545) feed with one monthly recurring event per active subscription of the user
+// @Tags subscriptions
+// @Produce text/calendar
+// @Param user_id path string true "User ID"
+// @Success 200 {string} string "iCalendar feed"
+// @Failure 400 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
+// @Router /api/users/{user_id}/renewals.ics [get]
+func (h *GetRenewalsCalendarHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context(), h.logger)
+
+	idStr := r.PathValue("user_id")
+	userID, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.Error("invalid user id", "user_id", idStr, "error", err)
+		problem.Write(w, r, http.StatusBadRequest, "invalid user id")
+		return
+	}
+
+	subscriptions, err := h.subscriptionService.ListActiveSubscriptions(r.Context(), userID)
+	if err != nil {
+		var validationErr *model.ValidationError
+		if errors.As(err, &validationErr) {
+			logger.Info("invalid request", "error", err)
+			problem.Write(w, r, http.StatusBadRequest, validationErr.Message)
+			return
+		}
+		var forbiddenErr *model.ForbiddenError
+		if errors.As(err, &forbiddenErr) {
+			logger.Info("access denied", "error", err)
+			problem.Write(w, r, http.StatusForbidden, forbiddenErr.Message)
+			return
+		}
+		logger.Error("failed to list active subscriptions", "user_id", userID, "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, "")
+		return
+	}
+
+	t, _ := tenant.FromContext(r.Context())
+	var buf bytes.Buffer
+	if err := writeRenewals(&buf, subscriptions, t.DefaultCurrency); err != nil {
+		logger.Error("failed to write calendar", "user_id", userID, "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, "")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="renewals.ics"`)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		logger.Error("failed to write response", "error", err)
+	}
+}
@@ -10,6 +10,8 @@ import (
 	"time"
 
 	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/infra/http/problem"
+	"github.com/golangtestcases/subscribe-service/internal/infra/logging"
 	"github.com/google/uuid"
 )
 
@@ -35,28 +37,37 @@ func NewGetSubscriptionHandler(subscriptionService SubscriptionService, logger *
 // @Produce json
 // @Param id path string true "Subscription ID"
 // @Success 200 {object} GetSubscriptionResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 404 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Failure 400 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
 // @Router /api/subscriptions/{id} [get]
 func (h *GetSubscriptionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context(), h.logger)
+
 	idStr := r.PathValue("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		h.logger.Error("invalid subscription id", "id", idStr, "error", err)
-		http.Error(w, "invalid subscription id", http.StatusBadRequest)
+		logger.Error("invalid subscription id", "id", idStr, "error", err)
+		problem.Write(w, r, http.StatusBadRequest, "invalid subscription id")
 		return
 	}
 
 	subscription, err := h.subscriptionService.GetSubscriptionByID(r.Context(), id)
 	if err != nil {
+		var validationErr *model.ValidationError
+		if errors.As(err, &validationErr) {
+			logger.Info("invalid request", "error", err)
+			problem.Write(w, r, http.StatusBadRequest, validationErr.Message)
+			return
+		}
 		if errors.Is(err, sql.ErrNoRows) {
-			h.logger.Info("subscription not found", "id", id)
-			http.Error(w, "subscription not found", http.StatusNotFound)
+			logger.Info("subscription not found", "id", id)
+			problem.Write(w, r, http.StatusNotFound, "subscription not found")
 			return
 		}
-		h.logger.Error("failed to get subscription", "id", id, "error", err)
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+		logger.Error("failed to get subscription", "id", id, "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, "")
 		return
 	}
 
@@ -71,7 +82,7 @@ func (h *GetSubscriptionHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(response); err != nil {
-		h.logger.Error("failed to encode response", "error", err)
+		logger.Error("failed to encode response", "error", err)
 	}
 }
 
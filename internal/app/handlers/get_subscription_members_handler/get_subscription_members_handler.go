@@ -0,0 +1,82 @@
+package get_subscription_members_handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/infra/http/problem"
+	"github.com/golangtestcases/subscribe-service/internal/infra/logging"
+	"github.com/google/uuid"
+)
+
+type SubscriptionService interface {
+	ListSubscriptionMembers(ctx context.Context, subscriptionID uuid.UUID) ([]model.SubscriptionMember, error)
+}
+
+type GetSubscriptionMembersHandler struct {
+	subscriptionService SubscriptionService
+	logger              *slog.Logger
+}
+
+func NewGetSubscriptionMembersHandler(subscriptionService SubscriptionService, logger *slog.Logger) *GetSubscriptionMembersHandler {
+	return &GetSubscriptionMembersHandler{
+		subscriptionService: subscriptionService,
+		logger:              logger,
+	}
+}
+
+// @Summary Get subscription members
+// @Description Get users sharing a subscription and their shares of the price
+// @Tags subscriptions
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Success 200 {object} GetSubscriptionMembersResponse
+// @Failure 400 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
+// @Router /api/subscriptions/{id}/members [get]
+func (h *GetSubscriptionMembersHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context(), h.logger)
+
+	idStr := r.PathValue("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.Error("invalid subscription id", "id", idStr, "error", err)
+		problem.Write(w, r, http.StatusBadRequest, "invalid subscription id")
+		return
+	}
+
+	members, err := h.subscriptionService.ListSubscriptionMembers(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logger.Info("subscription not found", "id", id)
+			problem.Write(w, r, http.StatusNotFound, "subscription not found")
+			return
+		}
+		logger.Error("failed to list subscription members", "id", id, "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, "")
+		return
+	}
+
+	response := GetSubscriptionMembersResponse{
+		SubscriptionID: id.String(),
+		Members:        []MemberItem{},
+	}
+	for _, member := range members {
+		response.Members = append(response.Members, MemberItem{
+			UserID: member.UserID.String(),
+			Share:  member.Share,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Error("failed to encode response", "error", err)
+	}
+}
@@ -0,0 +1,11 @@
+package get_subscription_members_handler
+
+type GetSubscriptionMembersResponse struct {
+	SubscriptionID string       `json:"subscription_id"`
+	Members        []MemberItem `json:"members"`
+}
+
+type MemberItem struct {
+	UserID string  `json:"user_id"`
+	Share  float64 `json:"share"`
+}
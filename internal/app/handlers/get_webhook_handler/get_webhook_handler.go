@@ -0,0 +1,83 @@
+package get_webhook_handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/infra/http/problem"
+	"github.com/golangtestcases/subscribe-service/internal/infra/logging"
+	"github.com/google/uuid"
+)
+
+type WebhookService interface {
+	GetWebhookByID(ctx context.Context, id uuid.UUID) (model.Webhook, error)
+}
+
+type GetWebhookHandler struct {
+	webhookService WebhookService
+	logger         *slog.Logger
+}
+
+func NewGetWebhookHandler(webhookService WebhookService, logger *slog.Logger) *GetWebhookHandler {
+	return &GetWebhookHandler{
+		webhookService: webhookService,
+		logger:         logger,
+	}
+}
+
+// @Summary Get webhook
+// @Description Get webhook by ID. The secret is not returned
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} GetWebhookResponse
+// @Failure 400 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
+// @Router /api/webhooks/{id} [get]
+func (h *GetWebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context(), h.logger)
+
+	idStr := r.PathValue("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.Error("invalid webhook id", "id", idStr, "error", err)
+		problem.Write(w, r, http.StatusBadRequest, "invalid webhook id")
+		return
+	}
+
+	webhook, err := h.webhookService.GetWebhookByID(r.Context(), id)
+	if err != nil {
+		var validationErr *model.ValidationError
+		if errors.As(err, &validationErr) {
+			logger.Info("invalid request", "error", err)
+			problem.Write(w, r, http.StatusBadRequest, validationErr.Message)
+			return
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			logger.Info("webhook not found", "id", id)
+			problem.Write(w, r, http.StatusNotFound, "webhook not found")
+			return
+		}
+		logger.Error("failed to get webhook", "id", id, "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, "")
+		return
+	}
+
+	response := GetWebhookResponse{
+		ID:         webhook.ID.String(),
+		URL:        webhook.URL,
+		EventTypes: webhook.EventTypes,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Error("failed to encode response", "error", err)
+	}
+}
@@ -0,0 +1,9 @@
+package get_webhook_handler
+
+import "github.com/golangtestcases/subscribe-service/internal/domain/model"
+
+type GetWebhookResponse struct {
+	ID         string            `json:"id"`
+	URL        string            `json:"url"`
+	EventTypes []model.EventType `json:"event_types"`
+}
@@ -0,0 +1,142 @@
+package import_subscriptions_handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/infra/http/problem"
+	"github.com/golangtestcases/subscribe-service/internal/infra/logging"
+)
+
+// maxFileSize limits the size of an uploaded CSV file.
+const maxFileSize = 10 << 20
+
+type SubscriptionService interface {
+	ValidateSubscription(subscription model.Subscription) error
+	ImportSubscriptions(ctx context.Context, subscriptions []model.Subscription, dryRun bool) ([]model.Subscription, error)
+}
+
+type ImportSubscriptionsHandler struct {
+	subscriptionService SubscriptionService
+	logger              *slog.Logger
+}
+
+func NewImportSubscriptionsHandler(subscriptionService SubscriptionService, logger *slog.Logger) *ImportSubscriptionsHandler {
+	return &ImportSubscriptionsHandler{
+		subscriptionService: subscriptionService,
+		logger:              logger,
+	}
+}
+
+// @Summary Import subscriptions from CSV
+// @Description Import subscriptions from a CSV file with a header row and the columns service_name, price, user_id, start_date and optional end_date. The file is sent as the "file" field of a multipart form or as a text/csv body. Valid rows are imported in one transaction; invalid rows are reported by line.
+// @Tags subscriptions
+// @Accept multipart/form-data,text/csv
+// @Produce json
+// @Param file formData file false "CSV file"
+// @Param dry_run query bool false "Only validate, do not import" default(false)
+// @Success 200 {object} ImportSubscriptionsResponse
+// @Failure 400 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
+// @Router /api/subscriptions/import [post]
+func (h *ImportSubscriptionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context(), h.logger)
+
+	defer r.Body.Close()
+
+	dryRun := false
+	if dryRunStr := r.URL.Query().Get("dry_run"); dryRunStr != "" {
+		parsed, err := strconv.ParseBool(dryRunStr)
+		if err != nil {
+			problem.Write(w, r, http.StatusBadRequest, "invalid dry_run, expected true or false")
+			return
+		}
+		dryRun = parsed
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxFileSize)
+
+	file, err := h.openFile(r)
+	if err != nil {
+		logger.Error("failed to read uploaded file", "error", err)
+		problem.Write(w, r, http.StatusBadRequest, "invalid file: "+err.Error())
+		return
+	}
+	defer file.Close()
+
+	rows, err := readRows(file)
+	if err != nil {
+		logger.Error("failed to parse csv", "error", err)
+		problem.Write(w, r, http.StatusBadRequest, "invalid csv: "+err.Error())
+		return
+	}
+
+	response := ImportSubscriptionsResponse{
+		DryRun:    dryRun,
+		TotalRows: len(rows),
+		Errors:    []RowError{},
+	}
+
+	var subscriptions []model.Subscription
+	for _, row := range rows {
+		err := row.Err
+		var subscription model.Subscription
+		if err == nil {
+			subscription, err = row.ToModel()
+		}
+		if err == nil {
+			err = h.subscriptionService.ValidateSubscription(subscription)
+		}
+		if err != nil {
+			response.Errors = append(response.Errors, RowError{Line: row.Line, Error: err.Error()})
+			continue
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	response.ValidRows = len(subscriptions)
+
+	if len(subscriptions) > 0 {
+		imported, err := h.subscriptionService.ImportSubscriptions(r.Context(), subscriptions, dryRun)
+		if err != nil {
+			var forbiddenErr *model.ForbiddenError
+			if errors.As(err, &forbiddenErr) {
+				logger.Info("access denied", "error", err)
+				problem.Write(w, r, http.StatusForbidden, forbiddenErr.Message)
+				return
+			}
+			logger.Error("failed to import subscriptions", "error", err)
+			problem.Write(w, r, http.StatusInternalServerError, "")
+			return
+		}
+		response.Imported = len(imported)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Error("failed to encode response", "error", err)
+	}
+}
+
+// openFile returns the uploaded CSV, either the "file" field of a multipart
+// form or the raw request body.
+func (h *ImportSubscriptionsHandler) openFile(r *http.Request) (io.ReadCloser, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		return io.NopCloser(r.Body), nil
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
@@ -0,0 +1,127 @@
+package import_subscriptions_handler
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+var requiredColumns = []string{"service_name", "price", "user_id", "start_date"}
+
+// ImportRow is one data row of the CSV file. Its columns match
+// CreateSubscriptionRequest; Line is the line number in the file.
+type ImportRow struct {
+	Line        int
+	ServiceName string
+	Price       string
+	UserID      string
+	StartDate   string
+	EndDate     string
+	Err         error
+}
+
+// readRows reads the CSV file. The first line is a header naming the columns:
+// service_name, price, user_id and start_date are required, end_date is
+// optional. Rows that cannot be parsed are returned with Err set.
+func readRows(r io.Reader) ([]ImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("file is empty")
+		}
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, name := range requiredColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("missing column %q", name)
+		}
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var rows []ImportRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		// FieldPos is only valid after a successful Read: a parse error may
+		// come before any field of the record was read.
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			rows = append(rows, ImportRow{Line: parseErr.StartLine, Err: parseErr.Err})
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read row: %w", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := ImportRow{Line: line}
+		row.ServiceName = field(record, "service_name")
+		row.Price = field(record, "price")
+		row.UserID = field(record, "user_id")
+		row.StartDate = field(record, "start_date")
+		row.EndDate = field(record, "end_date")
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func (r *ImportRow) ToModel() (model.Subscription, error) {
+	price, err := strconv.Atoi(r.Price)
+	if err != nil {
+		return model.Subscription{}, fmt.Errorf("invalid price format: %w", err)
+	}
+
+	userID, err := uuid.Parse(r.UserID)
+	if err != nil {
+		return model.Subscription{}, fmt.Errorf("invalid user_id format: %w", err)
+	}
+
+	startDate, err := time.Parse("01-2006", r.StartDate)
+	if err != nil {
+		return model.Subscription{}, fmt.Errorf("invalid start_date format, expected MM-YYYY: %w", err)
+	}
+
+	var endDate *time.Time
+	if r.EndDate != "" {
+		parsed, err := time.Parse("01-2006", r.EndDate)
+		if err != nil {
+			return model.Subscription{}, fmt.Errorf("invalid end_date format, expected MM-YYYY: %w", err)
+		}
+		endDate = &parsed
+	}
+
+	return model.Subscription{
+		ServiceName: r.ServiceName,
+		Price:       price,
+		UserID:      userID,
+		StartDate:   startDate,
+		EndDate:     endDate,
+	}, nil
+}
@@ -0,0 +1,14 @@
+package import_subscriptions_handler
+
+type ImportSubscriptionsResponse struct {
+	DryRun    bool       `json:"dry_run"`
+	TotalRows int        `json:"total_rows"`
+	ValidRows int        `json:"valid_rows"`
+	Imported  int        `json:"imported"`
+	Errors    []RowError `json:"errors"`
+}
+
+type RowError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
@@ -0,0 +1,86 @@
+package issue_api_key_handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/infra/http/problem"
+	"github.com/golangtestcases/subscribe-service/internal/infra/logging"
+)
+
+type APIKeyService interface {
+	IssueAPIKey(ctx context.Context, name string, roles []model.Role) (model.APIKey, string, error)
+}
+
+type IssueAPIKeyHandler struct {
+	apiKeyService APIKeyService
+	logger        *slog.Logger
+}
+
+func NewIssueAPIKeyHandler(apiKeyService APIKeyService, logger *slog.Logger) *IssueAPIKeyHandler {
+	return &IssueAPIKeyHandler{
+		apiKeyService: apiKeyService,
+		logger:        logger,
+	}
+}
+
+// @Summary Issue API key
+// @Description Issue a key with the given roles: viewer, support, editor or admin. The key is returned only in this response; only its hash is stored
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Param api_key body IssueAPIKeyRequest true "API key data"
+// @Success 201 {object} IssueAPIKeyResponse
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
+// @Router /api/api-keys [post]
+func (h *IssueAPIKeyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context(), h.logger)
+
+	defer r.Body.Close()
+
+	var req IssueAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error("failed to decode request", "error", err)
+		problem.Write(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	apiKey, key, err := h.apiKeyService.IssueAPIKey(r.Context(), req.Name, req.Roles)
+	if err != nil {
+		var validationErr *model.ValidationError
+		if errors.As(err, &validationErr) {
+			logger.Info("invalid api key", "error", err)
+			problem.Write(w, r, http.StatusBadRequest, validationErr.Message)
+			return
+		}
+		logger.Error("failed to issue api key", "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, "")
+		return
+	}
+
+	logger.Info("api key issued", "id", apiKey.ID, "roles", apiKey.Roles)
+
+	response := IssueAPIKeyResponse{
+		ID:        apiKey.ID.String(),
+		Name:      apiKey.Name,
+		Prefix:    apiKey.Prefix,
+		Roles:     apiKey.Roles,
+		CreatedAt: apiKey.CreatedAt,
+		Key:       key,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Error("failed to encode response", "error", err)
+	}
+}
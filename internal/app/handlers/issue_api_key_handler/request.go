@@ -0,0 +1,8 @@
+package issue_api_key_handler
+
+import "github.com/golangtestcases/subscribe-service/internal/domain/model"
+
+type IssueAPIKeyRequest struct {
+	Name  string       `json:"name" example:"billing-dashboard"`
+	Roles []model.Role `json:"roles" example:"viewer"`
+}
@@ -0,0 +1,16 @@
+package issue_api_key_handler
+
+import (
+	"time"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+)
+
+type IssueAPIKeyResponse struct {
+	ID        string       `json:"id"`
+	Name      string       `json:"name"`
+	Prefix    string       `json:"prefix"`
+	Roles     []model.Role `json:"roles"`
+	CreatedAt time.Time    `json:"created_at"`
+	Key       string       `json:"key"`
+}
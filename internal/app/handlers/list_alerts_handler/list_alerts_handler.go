@@ -0,0 +1,91 @@
+package list_alerts_handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/infra/http/problem"
+	"github.com/golangtestcases/subscribe-service/internal/infra/logging"
+	"github.com/google/uuid"
+)
+
+type BudgetService interface {
+	ListAlerts(ctx context.Context, userID uuid.UUID) ([]model.BudgetAlert, error)
+}
+
+type ListAlertsHandler struct {
+	budgetService BudgetService
+	logger        *slog.Logger
+}
+
+func NewListAlertsHandler(budgetService BudgetService, logger *slog.Logger) *ListAlertsHandler {
+	return &ListAlertsHandler{
+		budgetService: budgetService,
+		logger:        logger,
+	}
+}
+
+// @Summary List budget alerts
+// @Description Get budget threshold alerts recorded for a user
+// @Tags budgets
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} ListAlertsResponse
+// @Failure 400 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
+// @Router /api/users/{id}/alerts [get]
+func (h *ListAlertsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context(), h.logger)
+
+	idStr := r.PathValue("id")
+	userID, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.Error("invalid user id", "id", idStr, "error", err)
+		problem.Write(w, r, http.StatusBadRequest, "invalid user id")
+		return
+	}
+
+	alerts, err := h.budgetService.ListAlerts(r.Context(), userID)
+	if err != nil {
+		var validationErr *model.ValidationError
+		if errors.As(err, &validationErr) {
+			logger.Info("invalid request", "error", err)
+			problem.Write(w, r, http.StatusBadRequest, validationErr.Message)
+			return
+		}
+		var forbiddenErr *model.ForbiddenError
+		if errors.As(err, &forbiddenErr) {
+			logger.Info("access denied", "error", err)
+			problem.Write(w, r, http.StatusForbidden, forbiddenErr.Message)
+			return
+		}
+		logger.Error("failed to list alerts", "user_id", userID, "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, "")
+		return
+	}
+
+	items := []AlertItem{}
+	for _, alert := range alerts {
+		items = append(items, AlertItem{
+			ID:        alert.ID.String(),
+			BudgetID:  alert.BudgetID.String(),
+			Threshold: alert.Threshold,
+			Spend:     alert.Spend,
+			Amount:    alert.Amount,
+			Month:     alert.Month.Format("01-2006"),
+			CreatedAt: alert.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(ListAlertsResponse{Items: items}); err != nil {
+		logger.Error("failed to encode response", "error", err)
+	}
+}
@@ -0,0 +1,15 @@
+package list_alerts_handler
+
+type ListAlertsResponse struct {
+	Items []AlertItem `json:"items"`
+}
+
+type AlertItem struct {
+	ID        string `json:"id"`
+	BudgetID  string `json:"budget_id"`
+	Threshold int    `json:"threshold"`
+	Spend     int    `json:"spend"`
+	Amount    int    `json:"amount"`
+	Month     string `json:"month"`
+	CreatedAt string `json:"created_at"`
+}
@@ -0,0 +1,66 @@
+package list_api_keys_handler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/infra/http/problem"
+	"github.com/golangtestcases/subscribe-service/internal/infra/logging"
+)
+
+type APIKeyService interface {
+	ListAPIKeys(ctx context.Context) ([]model.APIKey, error)
+}
+
+type ListAPIKeysHandler struct {
+	apiKeyService APIKeyService
+	logger        *slog.Logger
+}
+
+func NewListAPIKeysHandler(apiKeyService APIKeyService, logger *slog.Logger) *ListAPIKeysHandler {
+	return &ListAPIKeysHandler{
+		apiKeyService: apiKeyService,
+		logger:        logger,
+	}
+}
+
+// @Summary List API keys
+// @Description Get all issued API keys, including revoked ones. Keys themselves are not returned, only their prefixes
+// @Tags api-keys
+// @Produce json
+// @Success 200 {object} ListAPIKeysResponse
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
+// @Router /api/api-keys [get]
+func (h *ListAPIKeysHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context(), h.logger)
+
+	apiKeys, err := h.apiKeyService.ListAPIKeys(r.Context())
+	if err != nil {
+		logger.Error("failed to list api keys", "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, "")
+		return
+	}
+
+	items := []APIKeyItem{}
+	for _, apiKey := range apiKeys {
+		items = append(items, APIKeyItem{
+			ID:        apiKey.ID.String(),
+			Name:      apiKey.Name,
+			Prefix:    apiKey.Prefix,
+			Roles:     apiKey.Roles,
+			CreatedAt: apiKey.CreatedAt,
+			RevokedAt: apiKey.RevokedAt,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(ListAPIKeysResponse{Items: items}); err != nil {
+		logger.Error("failed to encode response", "error", err)
+	}
+}
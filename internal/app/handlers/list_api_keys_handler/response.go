@@ -0,0 +1,20 @@
+package list_api_keys_handler
+
+import (
+	"time"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+)
+
+type ListAPIKeysResponse struct {
+	Items []APIKeyItem `json:"items"`
+}
+
+type APIKeyItem struct {
+	ID        string       `json:"id"`
+	Name      string       `json:"name"`
+	Prefix    string       `json:"prefix"`
+	Roles     []model.Role `json:"roles"`
+	CreatedAt time.Time    `json:"created_at"`
+	RevokedAt *time.Time   `json:"revoked_at,omitempty"`
+}
@@ -0,0 +1,87 @@
+package list_budgets_handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/infra/http/problem"
+	"github.com/golangtestcases/subscribe-service/internal/infra/logging"
+	"github.com/google/uuid"
+)
+
+type BudgetService interface {
+	ListBudgets(ctx context.Context, userID uuid.UUID) ([]model.Budget, error)
+}
+
+type ListBudgetsHandler struct {
+	budgetService BudgetService
+	logger        *slog.Logger
+}
+
+func NewListBudgetsHandler(budgetService BudgetService, logger *slog.Logger) *ListBudgetsHandler {
+	return &ListBudgetsHandler{
+		budgetService: budgetService,
+		logger:        logger,
+	}
+}
+
+// @Summary List budgets
+// @Description Get all budgets of a user
+// @Tags budgets
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} ListBudgetsResponse
+// @Failure 400 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
+// @Router /api/users/{id}/budgets [get]
+func (h *ListBudgetsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context(), h.logger)
+
+	idStr := r.PathValue("id")
+	userID, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.Error("invalid user id", "id", idStr, "error", err)
+		problem.Write(w, r, http.StatusBadRequest, "invalid user id")
+		return
+	}
+
+	budgets, err := h.budgetService.ListBudgets(r.Context(), userID)
+	if err != nil {
+		var validationErr *model.ValidationError
+		if errors.As(err, &validationErr) {
+			logger.Info("invalid request", "error", err)
+			problem.Write(w, r, http.StatusBadRequest, validationErr.Message)
+			return
+		}
+		var forbiddenErr *model.ForbiddenError
+		if errors.As(err, &forbiddenErr) {
+			logger.Info("access denied", "error", err)
+			problem.Write(w, r, http.StatusForbidden, forbiddenErr.Message)
+			return
+		}
+		logger.Error("failed to list budgets", "user_id", userID, "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, "")
+		return
+	}
+
+	items := []BudgetItem{}
+	for _, budget := range budgets {
+		items = append(items, BudgetItem{
+			ID:          budget.ID.String(),
+			UserID:      budget.UserID.String(),
+			ServiceName: budget.ServiceName,
+			Amount:      budget.Amount,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(ListBudgetsResponse{Items: items}); err != nil {
+		logger.Error("failed to encode response", "error", err)
+	}
+}
@@ -0,0 +1,12 @@
+package list_budgets_handler
+
+type ListBudgetsResponse struct {
+	Items []BudgetItem `json:"items"`
+}
+
+type BudgetItem struct {
+	ID          string  `json:"id"`
+	UserID      string  `json:"user_id"`
+	ServiceName *string `json:"service_name,omitempty"`
+	Amount      int     `json:"amount"`
+}
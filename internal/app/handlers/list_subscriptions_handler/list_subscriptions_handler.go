@@ -9,6 +9,8 @@ import (
 	"time"
 
 	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/infra/http/problem"
+	"github.com/golangtestcases/subscribe-service/internal/infra/logging"
 )
 
 type SubscriptionService interface {
@@ -34,10 +36,13 @@ func NewListSubscriptionsHandler(subscriptionService SubscriptionService, logger
 // @Param limit query int false "Limit" default(10)
 // @Param offset query int false "Offset" default(0)
 // @Success 200 {object} ListSubscriptionsResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Failure 400 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
 // @Router /api/subscriptions [get]
 func (h *ListSubscriptionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context(), h.logger)
+
 	limit := 10
 	offset := 0
 
@@ -55,8 +60,8 @@ func (h *ListSubscriptionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Requ
 
 	subscriptions, err := h.subscriptionService.ListSubscriptions(r.Context(), limit, offset)
 	if err != nil {
-		h.logger.Error("failed to list subscriptions", "error", err)
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+		logger.Error("failed to list subscriptions", "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, "")
 		return
 	}
 
@@ -81,7 +86,7 @@ func (h *ListSubscriptionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Requ
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(response); err != nil {
-		h.logger.Error("failed to encode response", "error", err)
+		logger.Error("failed to encode response", "error", err)
 	}
 }
 
@@ -15,7 +15,3 @@ type SubscriptionItem struct {
 	StartDate   string  `json:"start_date"`
 	EndDate     *string `json:"end_date,omitempty"`
 }
-
-type ErrorResponse struct {
-	Error string `json:"error"`
-}
\ No newline at end of file
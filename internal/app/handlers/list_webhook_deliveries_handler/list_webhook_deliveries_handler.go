@@ -0,0 +1,127 @@
+package list_webhook_deliveries_handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/infra/http/problem"
+	"github.com/golangtestcases/subscribe-service/internal/infra/logging"
+	"github.com/google/uuid"
+)
+
+type WebhookService interface {
+	ListDeliveries(ctx context.Context, webhookID uuid.UUID, limit, offset int) ([]model.WebhookDelivery, error)
+}
+
+type ListWebhookDeliveriesHandler struct {
+	webhookService WebhookService
+	logger         *slog.Logger
+}
+
+func NewListWebhookDeliveriesHandler(webhookService WebhookService, logger *slog.Logger) *ListWebhookDeliveriesHandler {
+	return &ListWebhookDeliveriesHandler{
+		webhookService: webhookService,
+		logger:         logger,
+	}
+}
+
+// @Summary List webhook deliveries
+// @Description Get the delivery log of a webhook, newest first
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Param limit query int false "Limit" default(10)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} ListWebhookDeliveriesResponse
+// @Failure 400 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
+// @Router /api/webhooks/{id}/deliveries [get]
+func (h *ListWebhookDeliveriesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context(), h.logger)
+
+	idStr := r.PathValue("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.Error("invalid webhook id", "id", idStr, "error", err)
+		problem.Write(w, r, http.StatusBadRequest, "invalid webhook id")
+		return
+	}
+
+	limit := 10
+	offset := 0
+
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			limit = l
+		}
+	}
+
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
+			offset = o
+		}
+	}
+
+	deliveries, err := h.webhookService.ListDeliveries(r.Context(), id, limit, offset)
+	if err != nil {
+		var validationErr *model.ValidationError
+		if errors.As(err, &validationErr) {
+			logger.Info("invalid request", "error", err)
+			problem.Write(w, r, http.StatusBadRequest, validationErr.Message)
+			return
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			logger.Info("webhook not found", "id", id)
+			problem.Write(w, r, http.StatusNotFound, "webhook not found")
+			return
+		}
+		logger.Error("failed to list webhook deliveries", "id", id, "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, "")
+		return
+	}
+
+	items := []DeliveryItem{}
+	for _, delivery := range deliveries {
+		items = append(items, DeliveryItem{
+			ID:             delivery.ID.String(),
+			EventID:        delivery.EventID.String(),
+			EventType:      delivery.EventType,
+			Status:         delivery.Status,
+			Attempts:       delivery.Attempts,
+			ResponseStatus: delivery.ResponseStatus,
+			LastError:      delivery.LastError,
+			NextAttemptAt:  formatNextAttempt(delivery),
+			DeliveredAt:    delivery.DeliveredAt,
+			CreatedAt:      delivery.CreatedAt,
+		})
+	}
+
+	response := ListWebhookDeliveriesResponse{
+		Items:  items,
+		Limit:  limit,
+		Offset: offset,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Error("failed to encode response", "error", err)
+	}
+}
+
+// formatNextAttempt returns when the delivery is retried; finished deliveries
+// have none.
+func formatNextAttempt(delivery model.WebhookDelivery) *time.Time {
+	if delivery.Status != model.DeliveryStatusPending {
+		return nil
+	}
+	return &delivery.NextAttemptAt
+}
@@ -0,0 +1,26 @@
+package list_webhook_deliveries_handler
+
+import (
+	"time"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+)
+
+type ListWebhookDeliveriesResponse struct {
+	Items  []DeliveryItem `json:"items"`
+	Limit  int            `json:"limit"`
+	Offset int            `json:"offset"`
+}
+
+type DeliveryItem struct {
+	ID             string               `json:"id"`
+	EventID        string               `json:"event_id"`
+	EventType      model.EventType      `json:"event_type"`
+	Status         model.DeliveryStatus `json:"status"`
+	Attempts       int                  `json:"attempts"`
+	ResponseStatus *int                 `json:"response_status,omitempty"`
+	LastError      *string              `json:"last_error,omitempty"`
+	NextAttemptAt  *time.Time           `json:"next_attempt_at,omitempty"`
+	DeliveredAt    *time.Time           `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time            `json:"created_at"`
+}
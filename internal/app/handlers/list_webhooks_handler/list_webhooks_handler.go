@@ -0,0 +1,61 @@
+package list_webhooks_handler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/infra/http/problem"
+	"github.com/golangtestcases/subscribe-service/internal/infra/logging"
+)
+
+type WebhookService interface {
+	ListWebhooks(ctx context.Context) ([]model.Webhook, error)
+}
+
+type ListWebhooksHandler struct {
+	webhookService WebhookService
+	logger         *slog.Logger
+}
+
+func NewListWebhooksHandler(webhookService WebhookService, logger *slog.Logger) *ListWebhooksHandler {
+	return &ListWebhooksHandler{
+		webhookService: webhookService,
+		logger:         logger,
+	}
+}
+
+// @Summary List webhooks
+// @Description Get all registered webhooks. Secrets are not returned
+// @Tags webhooks
+// @Produce json
+// @Success 200 {object} ListWebhooksResponse
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
+// @Router /api/webhooks [get]
+func (h *ListWebhooksHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context(), h.logger)
+
+	webhooks, err := h.webhookService.ListWebhooks(r.Context())
+	if err != nil {
+		logger.Error("failed to list webhooks", "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, "")
+		return
+	}
+
+	items := []WebhookItem{}
+	for _, webhook := range webhooks {
+		items = append(items, WebhookItem{
+			ID:         webhook.ID.String(),
+			URL:        webhook.URL,
+			EventTypes: webhook.EventTypes,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(ListWebhooksResponse{Items: items}); err != nil {
+		logger.Error("failed to encode response", "error", err)
+	}
+}
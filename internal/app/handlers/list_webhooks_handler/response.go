@@ -0,0 +1,13 @@
+package list_webhooks_handler
+
+import "github.com/golangtestcases/subscribe-service/internal/domain/model"
+
+type ListWebhooksResponse struct {
+	Items []WebhookItem `json:"items"`
+}
+
+type WebhookItem struct {
+	ID         string            `json:"id"`
+	URL        string            `json:"url"`
+	EventTypes []model.EventType `json:"event_types"`
+}
@@ -0,0 +1,34 @@
+package liveness_handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/golangtestcases/subscribe-service/internal/infra/logging"
+)
+
+type LivenessHandler struct {
+	logger *slog.Logger
+}
+
+func NewLivenessHandler(logger *slog.Logger) *LivenessHandler {
+	return &LivenessHandler{
+		logger: logger,
+	}
+}
+
+// @Summary Liveness probe
+// @Description Reports that the process is running. Dependencies are not checked, so a database outage does not get the service restarted
+// @Tags health
+// @Produce json
+// @Success 200 {object} LivenessResponse
+// @Router /healthz [get]
+func (h *LivenessHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context(), h.logger)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(LivenessResponse{Status: "ok"}); err != nil {
+		logger.Error("failed to encode response", "error", err)
+	}
+}
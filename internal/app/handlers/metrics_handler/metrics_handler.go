@@ -0,0 +1,47 @@
+package metrics_handler
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/golangtestcases/subscribe-service/internal/infra/logging"
+)
+
+type MetricsRegistry interface {
+	WriteTo(ctx context.Context, w io.Writer) error
+}
+
+type MetricsHandler struct {
+	metricsRegistry MetricsRegistry
+	logger          *slog.Logger
+}
+
+func NewMetricsHandler(metricsRegistry MetricsRegistry, logger *slog.Logger) *MetricsHandler {
+	return &MetricsHandler{
+		metricsRegistry: metricsRegistry,
+		logger:          logger,
+	}
+}
+
+// @Summary Prometheus metrics
+// @Description Request counts and latencies per route, database connection pool statistics and subscription gauges in the Prometheus text format. Metrics that fail to collect are left out
+// @Tags health
+// @Produce plain
+// @Success 200 {string} string
+// @Router /metrics [get]
+func (h *MetricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context(), h.logger)
+
+	var buf bytes.Buffer
+	if err := h.metricsRegistry.WriteTo(r.Context(), &buf); err != nil {
+		logger.Error("failed to collect metrics", "error", err)
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		logger.Error("failed to write metrics", "error", err)
+	}
+}
@@ -0,0 +1,75 @@
+package readiness_handler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/infra/logging"
+)
+
+type HealthChecker interface {
+	Readiness(ctx context.Context) model.Readiness
+}
+
+type ReadinessHandler struct {
+	healthChecker HealthChecker
+	logger        *slog.Logger
+}
+
+func NewReadinessHandler(healthChecker HealthChecker, logger *slog.Logger) *ReadinessHandler {
+	return &ReadinessHandler{
+		healthChecker: healthChecker,
+		logger:        logger,
+	}
+}
+
+// @Summary Readiness probe
+// @Description Checks the database connection and the migration version and reports the status of each dependency. Returns 503 if a required dependency fails or the service is shutting down
+// @Tags health
+// @Produce json
+// @Success 200 {object} ReadinessResponse
+// @Failure 503 {object} ReadinessResponse
+// @Router /readyz [get]
+func (h *ReadinessHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context(), h.logger)
+
+	readiness := h.healthChecker.Readiness(r.Context())
+
+	response := ReadinessResponse{
+		Status:       "ready",
+		Dependencies: make([]DependencyResponse, 0, len(readiness.Dependencies)),
+	}
+	for _, dependency := range readiness.Dependencies {
+		item := DependencyResponse{
+			Name:     dependency.Name,
+			Status:   "ok",
+			Optional: dependency.Optional,
+		}
+		if dependency.Error != nil {
+			item.Status = "failed"
+			item.Error = dependency.Error.Error()
+		}
+		response.Dependencies = append(response.Dependencies, item)
+	}
+
+	status := http.StatusOK
+	switch {
+	case readiness.ShuttingDown:
+		response.Status = "shutting_down"
+		status = http.StatusServiceUnavailable
+	case !readiness.Ready():
+		response.Status = "not_ready"
+		status = http.StatusServiceUnavailable
+		logger.Warn("service is not ready", "dependencies", response.Dependencies)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Error("failed to encode response", "error", err)
+	}
+}
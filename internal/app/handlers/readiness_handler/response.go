@@ -0,0 +1,15 @@
+package readiness_handler
+
+// ReadinessResponse has the status "ready", "not_ready" or "shutting_down".
+type ReadinessResponse struct {
+	Status       string               `json:"status"`
+	Dependencies []DependencyResponse `json:"dependencies"`
+}
+
+// DependencyResponse has the status "ok" or "failed".
+type DependencyResponse struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Optional bool   `json:"optional,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
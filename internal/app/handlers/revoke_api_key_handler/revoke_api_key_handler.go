@@ -0,0 +1,76 @@
+package revoke_api_key_handler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/infra/http/problem"
+	"github.com/golangtestcases/subscribe-service/internal/infra/logging"
+	"github.com/google/uuid"
+)
+
+type APIKeyService interface {
+	RevokeAPIKey(ctx context.Context, id uuid.UUID) error
+}
+
+type RevokeAPIKeyHandler struct {
+	apiKeyService APIKeyService
+	logger        *slog.Logger
+}
+
+func NewRevokeAPIKeyHandler(apiKeyService APIKeyService, logger *slog.Logger) *RevokeAPIKeyHandler {
+	return &RevokeAPIKeyHandler{
+		apiKeyService: apiKeyService,
+		logger:        logger,
+	}
+}
+
+// @Summary Revoke API key
+// @Description Revoke API key by ID. Requests with the key are rejected from then on; the key stays listed with its revocation time
+// @Tags api-keys
+// @Param id path string true "API key ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
+// @Router /api/api-keys/{id} [delete]
+func (h *RevokeAPIKeyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context(), h.logger)
+
+	idStr := r.PathValue("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.Error("invalid api key id", "id", idStr, "error", err)
+		problem.Write(w, r, http.StatusBadRequest, "invalid api key id")
+		return
+	}
+
+	err = h.apiKeyService.RevokeAPIKey(r.Context(), id)
+	if err != nil {
+		var validationErr *model.ValidationError
+		if errors.As(err, &validationErr) {
+			logger.Info("invalid request", "error", err)
+			problem.Write(w, r, http.StatusBadRequest, validationErr.Message)
+			return
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			logger.Info("api key not found", "id", id)
+			problem.Write(w, r, http.StatusNotFound, "api key not found or already revoked")
+			return
+		}
+		logger.Error("failed to revoke api key", "id", id, "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, "")
+		return
+	}
+
+	logger.Info("api key revoked", "id", id)
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,27 @@
+package suggest_subscriptions_handler
+
+type SuggestSubscriptionsResponse struct {
+	Transactions int              `json:"transactions"`
+	Suggestions  []SuggestionItem `json:"suggestions"`
+}
+
+// SuggestionItem is a detected recurring charge. Subscription has the shape of
+// CreateSubscriptionRequest and can be posted to /api/subscriptions as is.
+type SuggestionItem struct {
+	Subscription   SubscriptionItem `json:"subscription"`
+	Merchant       string           `json:"merchant"`
+	Cadence        string           `json:"cadence" enums:"monthly,yearly"`
+	ChargeAmount   int              `json:"charge_amount"`
+	Occurrences    int              `json:"occurrences"`
+	LastChargeDate string           `json:"last_charge_date"`
+	Confidence     float64          `json:"confidence"`
+	AlreadyTracked bool             `json:"already_tracked"`
+}
+
+type SubscriptionItem struct {
+	ServiceName string  `json:"service_name"`
+	Price       int     `json:"price"`
+	UserID      string  `json:"user_id"`
+	StartDate   string  `json:"start_date"`
+	EndDate     *string `json:"end_date,omitempty"`
+}
@@ -0,0 +1,152 @@
+package suggest_subscriptions_handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/infra/http/problem"
+	"github.com/golangtestcases/subscribe-service/internal/infra/logging"
+	"github.com/golangtestcases/subscribe-service/internal/infra/statement"
+	"github.com/google/uuid"
+)
+
+// maxFileSize limits the size of an uploaded bank statement.
+const maxFileSize = 10 << 20
+
+type SubscriptionService interface {
+	SuggestSubscriptions(ctx context.Context, userID uuid.UUID, transactions []model.Transaction) ([]model.SuggestedSubscription, error)
+}
+
+type SuggestSubscriptionsHandler struct {
+	subscriptionService SubscriptionService
+	logger              *slog.Logger
+}
+
+func NewSuggestSubscriptionsHandler(subscriptionService SubscriptionService, logger *slog.Logger) *SuggestSubscriptionsHandler {
+	return &SuggestSubscriptionsHandler{
+		subscriptionService: subscriptionService,
+		logger:              logger,
+	}
+}
+
+// @Summary Suggest subscriptions from a bank statement
+// @Description Detect recurring charges (same merchant, similar amount, monthly or yearly) in an uploaded CSV or OFX bank statement. The file is sent as the "file" field of a multipart form or as the raw body.
+// @Tags subscriptions
+// @Accept multipart/form-data,text/csv,application/x-ofx
+// @Produce json
+// @Param id path string true "User ID"
+// @Param file formData file false "Bank statement"
+// @Success 200 {object} SuggestSubscriptionsResponse
+// @Failure 400 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
+// @Router /api/users/{id}/subscription-suggestions [post]
+func (h *SuggestSubscriptionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context(), h.logger)
+
+	defer r.Body.Close()
+
+	idStr := r.PathValue("id")
+	userID, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.Error("invalid user id", "id", idStr, "error", err)
+		problem.Write(w, r, http.StatusBadRequest, "invalid user id")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxFileSize)
+
+	file, err := h.openFile(r)
+	if err != nil {
+		logger.Error("failed to read uploaded file", "error", err)
+		problem.Write(w, r, http.StatusBadRequest, "invalid file: "+err.Error())
+		return
+	}
+	defer file.Close()
+
+	transactions, err := statement.Parse(file)
+	if err != nil {
+		logger.Error("failed to parse bank statement", "error", err)
+		problem.Write(w, r, http.StatusBadRequest, "invalid bank statement: "+err.Error())
+		return
+	}
+
+	suggestions, err := h.subscriptionService.SuggestSubscriptions(r.Context(), userID, transactions)
+	if err != nil {
+		var validationErr *model.ValidationError
+		if errors.As(err, &validationErr) {
+			logger.Info("invalid request", "error", err)
+			problem.Write(w, r, http.StatusBadRequest, validationErr.Message)
+			return
+		}
+		var forbiddenErr *model.ForbiddenError
+		if errors.As(err, &forbiddenErr) {
+			logger.Info("access denied", "error", err)
+			problem.Write(w, r, http.StatusForbidden, forbiddenErr.Message)
+			return
+		}
+		logger.Error("failed to suggest subscriptions", "user_id", userID, "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, "")
+		return
+	}
+
+	response := SuggestSubscriptionsResponse{
+		Transactions: len(transactions),
+		Suggestions:  []SuggestionItem{},
+	}
+	for _, suggestion := range suggestions {
+		subscription := suggestion.Subscription
+		response.Suggestions = append(response.Suggestions, SuggestionItem{
+			Subscription: SubscriptionItem{
+				ServiceName: subscription.ServiceName,
+				Price:       subscription.Price,
+				UserID:      subscription.UserID.String(),
+				StartDate:   subscription.StartDate.Format("01-2006"),
+				EndDate:     formatEndDate(subscription.EndDate),
+			},
+			Merchant:       suggestion.Merchant,
+			Cadence:        string(suggestion.Cadence),
+			ChargeAmount:   suggestion.ChargeAmount,
+			Occurrences:    suggestion.Occurrences,
+			LastChargeDate: suggestion.LastChargeDate.Format("2006-01-02"),
+			Confidence:     suggestion.Confidence,
+			AlreadyTracked: suggestion.AlreadyTracked,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Error("failed to encode response", "error", err)
+	}
+}
+
+// openFile returns the uploaded statement, either the "file" field of a
+// multipart form or the raw request body.
+func (h *SuggestSubscriptionsHandler) openFile(r *http.Request) (io.ReadCloser, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		return io.NopCloser(r.Body), nil
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+func formatEndDate(endDate *time.Time) *string {
+	if endDate == nil {
+		return nil
+	}
+	formatted := endDate.Format("01-2006")
+	return &formatted
+}
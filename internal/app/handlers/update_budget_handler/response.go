@@ -0,0 +1,8 @@
+package update_budget_handler
+
+type UpdateBudgetResponse struct {
+	ID          string  `json:"id"`
+	UserID      string  `json:"user_id"`
+	ServiceName *string `json:"service_name,omitempty"`
+	Amount      int     `json:"amount"`
+}
@@ -0,0 +1,109 @@
+package update_budget_handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/infra/http/problem"
+	"github.com/golangtestcases/subscribe-service/internal/infra/logging"
+	"github.com/google/uuid"
+)
+
+type BudgetService interface {
+	UpdateBudget(ctx context.Context, budget model.Budget) error
+}
+
+type UpdateBudgetHandler struct {
+	budgetService BudgetService
+	logger        *slog.Logger
+}
+
+func NewUpdateBudgetHandler(budgetService BudgetService, logger *slog.Logger) *UpdateBudgetHandler {
+	return &UpdateBudgetHandler{
+		budgetService: budgetService,
+		logger:        logger,
+	}
+}
+
+// @Summary Update budget
+// @Description Update budget by ID
+// @Tags budgets
+// @Accept json
+// @Produce json
+// @Param id path string true "Budget ID"
+// @Param budget body UpdateBudgetRequest true "Budget data"
+// @Success 200 {object} UpdateBudgetResponse
+// @Failure 400 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
+// @Router /api/budgets/{id} [put]
+func (h *UpdateBudgetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context(), h.logger)
+
+	defer r.Body.Close()
+
+	idStr := r.PathValue("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.Error("invalid budget id", "id", idStr, "error", err)
+		problem.Write(w, r, http.StatusBadRequest, "invalid budget id")
+		return
+	}
+
+	var req UpdateBudgetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error("failed to decode request", "error", err)
+		problem.Write(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	budget, err := req.ToModel(id)
+	if err != nil {
+		logger.Error("failed to convert request to model", "error", err)
+		problem.Write(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = h.budgetService.UpdateBudget(r.Context(), budget)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logger.Info("budget not found", "id", id)
+			problem.Write(w, r, http.StatusNotFound, "budget not found")
+			return
+		}
+		var validationErr *model.ValidationError
+		if errors.As(err, &validationErr) {
+			logger.Info("invalid budget", "error", err)
+			problem.Write(w, r, http.StatusBadRequest, validationErr.Message)
+			return
+		}
+		var forbiddenErr *model.ForbiddenError
+		if errors.As(err, &forbiddenErr) {
+			logger.Info("access denied", "error", err)
+			problem.Write(w, r, http.StatusForbidden, forbiddenErr.Message)
+			return
+		}
+		logger.Error("failed to update budget", "id", id, "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, "")
+		return
+	}
+
+	response := UpdateBudgetResponse{
+		ID:          budget.ID.String(),
+		UserID:      budget.UserID.String(),
+		ServiceName: budget.ServiceName,
+		Amount:      budget.Amount,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Error("failed to encode response", "error", err)
+	}
+}
@@ -8,7 +8,3 @@ type UpdateSubscriptionResponse struct {
 	StartDate   string  `json:"start_date"`
 	EndDate     *string `json:"end_date,omitempty"`
 }
-
-type ErrorResponse struct {
-	Error string `json:"error"`
-}
\ No newline at end of file
@@ -10,6 +10,8 @@ import (
 	"time"
 
 	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/infra/http/problem"
+	"github.com/golangtestcases/subscribe-service/internal/infra/logging"
 	"github.com/google/uuid"
 )
 
@@ -37,44 +39,60 @@ func NewUpdateSubscriptionHandler(subscriptionService SubscriptionService, logge
 // @Param id path string true "Subscription ID"
 // @Param subscription body UpdateSubscriptionRequest true "Subscription data"
 // @Success 200 {object} UpdateSubscriptionResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 404 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Failure 400 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
 // @Router /api/subscriptions/{id} [put]
 func (h *UpdateSubscriptionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context(), h.logger)
+
 	defer r.Body.Close()
 
 	idStr := r.PathValue("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		h.logger.Error("invalid subscription id", "id", idStr, "error", err)
-		http.Error(w, "invalid subscription id", http.StatusBadRequest)
+		logger.Error("invalid subscription id", "id", idStr, "error", err)
+		problem.Write(w, r, http.StatusBadRequest, "invalid subscription id")
 		return
 	}
 
 	var req UpdateSubscriptionRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.logger.Error("failed to decode request", "error", err)
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+		logger.Error("failed to decode request", "error", err)
+		problem.Write(w, r, http.StatusBadRequest, "invalid request body")
 		return
 	}
 
 	subscription, err := req.ToModel(id)
 	if err != nil {
-		h.logger.Error("failed to convert request to model", "error", err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		logger.Error("failed to convert request to model", "error", err)
+		problem.Write(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	err = h.subscriptionService.UpdateSubscription(r.Context(), subscription)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			h.logger.Info("subscription not found", "id", id)
-			http.Error(w, "subscription not found", http.StatusNotFound)
+			logger.Info("subscription not found", "id", id)
+			problem.Write(w, r, http.StatusNotFound, "subscription not found")
+			return
+		}
+		var validationErr *model.ValidationError
+		if errors.As(err, &validationErr) {
+			logger.Info("invalid subscription", "error", err)
+			problem.Write(w, r, http.StatusBadRequest, validationErr.Message)
+			return
+		}
+		var forbiddenErr *model.ForbiddenError
+		if errors.As(err, &forbiddenErr) {
+			logger.Info("access denied", "error", err)
+			problem.Write(w, r, http.StatusForbidden, forbiddenErr.Message)
 			return
 		}
-		h.logger.Error("failed to update subscription", "id", id, "error", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		logger.Error("failed to update subscription", "id", id, "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, "")
 		return
 	}
 
@@ -89,7 +107,7 @@ func (h *UpdateSubscriptionHandler) ServeHTTP(w http.ResponseWriter, r *http.Req
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(response); err != nil {
-		h.logger.Error("failed to encode response", "error", err)
+		logger.Error("failed to encode response", "error", err)
 	}
 }
 
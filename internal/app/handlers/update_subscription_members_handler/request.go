@@ -0,0 +1,35 @@
+package update_subscription_members_handler
+
+import (
+	"fmt"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+type UpdateSubscriptionMembersRequest struct {
+	Members []MemberRequest `json:"members"`
+}
+
+type MemberRequest struct {
+	UserID string  `json:"user_id"`
+	Share  float64 `json:"share"`
+}
+
+func (r *UpdateSubscriptionMembersRequest) ToModel(subscriptionID uuid.UUID) ([]model.SubscriptionMember, error) {
+	members := make([]model.SubscriptionMember, 0, len(r.Members))
+	for _, member := range r.Members {
+		userID, err := uuid.Parse(member.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid user_id format: %w", err)
+		}
+
+		members = append(members, model.SubscriptionMember{
+			SubscriptionID: subscriptionID,
+			UserID:         userID,
+			Share:          member.Share,
+		})
+	}
+
+	return members, nil
+}
@@ -0,0 +1,11 @@
+package update_subscription_members_handler
+
+type UpdateSubscriptionMembersResponse struct {
+	SubscriptionID string       `json:"subscription_id"`
+	Members        []MemberItem `json:"members"`
+}
+
+type MemberItem struct {
+	UserID string  `json:"user_id"`
+	Share  float64 `json:"share"`
+}
@@ -0,0 +1,106 @@
+package update_subscription_members_handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/infra/http/problem"
+	"github.com/golangtestcases/subscribe-service/internal/infra/logging"
+	"github.com/google/uuid"
+)
+
+type SubscriptionService interface {
+	SetSubscriptionMembers(ctx context.Context, subscriptionID uuid.UUID, members []model.SubscriptionMember) error
+}
+
+type UpdateSubscriptionMembersHandler struct {
+	subscriptionService SubscriptionService
+	logger              *slog.Logger
+}
+
+func NewUpdateSubscriptionMembersHandler(subscriptionService SubscriptionService, logger *slog.Logger) *UpdateSubscriptionMembersHandler {
+	return &UpdateSubscriptionMembersHandler{
+		subscriptionService: subscriptionService,
+		logger:              logger,
+	}
+}
+
+// @Summary Update subscription members
+// @Description Replace the users sharing a subscription. Shares must add up to 1; an empty list removes all members.
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Param members body UpdateSubscriptionMembersRequest true "Members"
+// @Success 200 {object} UpdateSubscriptionMembersResponse
+// @Failure 400 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
+// @Router /api/subscriptions/{id}/members [put]
+func (h *UpdateSubscriptionMembersHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context(), h.logger)
+
+	defer r.Body.Close()
+
+	idStr := r.PathValue("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.Error("invalid subscription id", "id", idStr, "error", err)
+		problem.Write(w, r, http.StatusBadRequest, "invalid subscription id")
+		return
+	}
+
+	var req UpdateSubscriptionMembersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error("failed to decode request", "error", err)
+		problem.Write(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	members, err := req.ToModel(id)
+	if err != nil {
+		logger.Error("failed to convert request to model", "error", err)
+		problem.Write(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = h.subscriptionService.SetSubscriptionMembers(r.Context(), id, members)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logger.Info("subscription not found", "id", id)
+			problem.Write(w, r, http.StatusNotFound, "subscription not found")
+			return
+		}
+		var validationErr *model.ValidationError
+		if errors.As(err, &validationErr) {
+			logger.Info("invalid subscription members", "error", err)
+			problem.Write(w, r, http.StatusBadRequest, validationErr.Message)
+			return
+		}
+		logger.Error("failed to update subscription members", "id", id, "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, "")
+		return
+	}
+
+	response := UpdateSubscriptionMembersResponse{
+		SubscriptionID: id.String(),
+		Members:        []MemberItem{},
+	}
+	for _, member := range members {
+		response.Members = append(response.Members, MemberItem{
+			UserID: member.UserID.String(),
+			Share:  member.Share,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Error("failed to encode response", "error", err)
+	}
+}
@@ -0,0 +1,73 @@
+package duplicate_detection_job
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/infra/tenant"
+	"github.com/google/uuid"
+)
+
+type SubscriptionService interface {
+	FindDuplicates(ctx context.Context, userIDs []uuid.UUID) ([]model.DuplicateGroup, error)
+}
+
+// DuplicateDetectionJob periodically scans all users of every tenant for
+// duplicate subscriptions and logs what it finds.
+type DuplicateDetectionJob struct {
+	subscriptionService SubscriptionService
+	tenants             []model.Tenant
+	interval            time.Duration
+	logger              *slog.Logger
+}
+
+func NewDuplicateDetectionJob(subscriptionService SubscriptionService, tenants []model.Tenant, interval time.Duration, logger *slog.Logger) *DuplicateDetectionJob {
+	return &DuplicateDetectionJob{
+		subscriptionService: subscriptionService,
+		tenants:             tenants,
+		interval:            interval,
+		logger:              logger,
+	}
+}
+
+// Run executes the detection once immediately and then on every tick until ctx
+// is cancelled.
+func (j *DuplicateDetectionJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		for _, t := range j.tenants {
+			j.detect(tenant.WithTenant(ctx, t), j.logger.With("tenant", t.ID))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *DuplicateDetectionJob) detect(ctx context.Context, logger *slog.Logger) {
+	groups, err := j.subscriptionService.FindDuplicates(ctx, nil)
+	if err != nil {
+		logger.Error("failed to detect duplicate subscriptions", "error", err)
+		return
+	}
+
+	totalWasted := 0
+	for _, group := range groups {
+		totalWasted += group.WastedSpend
+		logger.Info("duplicate subscriptions found",
+			"service_name", group.ServiceName,
+			"user_ids", group.UserIDs,
+			"count", len(group.Subscriptions),
+			"wasted_spend", group.WastedSpend,
+		)
+	}
+
+	logger.Info("duplicate detection finished", "groups", len(groups), "total_wasted_spend", totalWasted)
+}
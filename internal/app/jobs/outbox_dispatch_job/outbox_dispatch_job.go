@@ -0,0 +1,70 @@
+package outbox_dispatch_job
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/infra/tenant"
+)
+
+type OutboxService interface {
+	DispatchPending(ctx context.Context) (int, error)
+}
+
+// OutboxDispatchJob polls the outbox of every tenant and publishes pending
+// events to the sinks.
+type OutboxDispatchJob struct {
+	outboxService OutboxService
+	tenants       []model.Tenant
+	interval      time.Duration
+	logger        *slog.Logger
+}
+
+func NewOutboxDispatchJob(outboxService OutboxService, tenants []model.Tenant, interval time.Duration, logger *slog.Logger) *OutboxDispatchJob {
+	return &OutboxDispatchJob{
+		outboxService: outboxService,
+		tenants:       tenants,
+		interval:      interval,
+		logger:        logger,
+	}
+}
+
+// Run dispatches pending events on every tick until ctx is cancelled. While
+// any tenant had a whole batch pending it continues without waiting for the
+// next tick.
+func (j *OutboxDispatchJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		for j.dispatch(ctx) && ctx.Err() == nil {
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// dispatch reports whether there may be more pending events.
+func (j *OutboxDispatchJob) dispatch(ctx context.Context) bool {
+	more := false
+	for _, t := range j.tenants {
+		logger := j.logger.With("tenant", t.ID)
+		dispatched, err := j.outboxService.DispatchPending(tenant.WithTenant(ctx, t))
+		if err != nil {
+			logger.Error("failed to dispatch outbox", "error", err)
+			continue
+		}
+
+		if dispatched > 0 {
+			logger.Debug("outbox events dispatched", "count", dispatched)
+			more = true
+		}
+	}
+	return more
+}
@@ -0,0 +1,65 @@
+package renewal_notification_job
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/infra/tenant"
+)
+
+type SubscriptionService interface {
+	PublishUpcomingRenewals(ctx context.Context, within time.Duration) (int, error)
+}
+
+// RenewalNotificationJob periodically announces upcoming subscription
+// renewals of every tenant as renewal.upcoming events.
+type RenewalNotificationJob struct {
+	subscriptionService SubscriptionService
+	tenants             []model.Tenant
+	interval            time.Duration
+	noticePeriod        time.Duration
+	logger              *slog.Logger
+}
+
+func NewRenewalNotificationJob(subscriptionService SubscriptionService, tenants []model.Tenant, interval, noticePeriod time.Duration, logger *slog.Logger) *RenewalNotificationJob {
+	return &RenewalNotificationJob{
+		subscriptionService: subscriptionService,
+		tenants:             tenants,
+		interval:            interval,
+		noticePeriod:        noticePeriod,
+		logger:              logger,
+	}
+}
+
+// Run checks for upcoming renewals once immediately and then on every tick
+// until ctx is cancelled.
+func (j *RenewalNotificationJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		for _, t := range j.tenants {
+			j.notify(tenant.WithTenant(ctx, t), j.logger.With("tenant", t.ID))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *RenewalNotificationJob) notify(ctx context.Context, logger *slog.Logger) {
+	announced, err := j.subscriptionService.PublishUpcomingRenewals(ctx, j.noticePeriod)
+	if err != nil {
+		logger.Error("failed to announce upcoming renewals", "error", err)
+		return
+	}
+
+	if announced > 0 {
+		logger.Info("upcoming renewals checked", "count", announced)
+	}
+}
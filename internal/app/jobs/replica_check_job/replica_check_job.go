@@ -0,0 +1,65 @@
+package replica_check_job
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+type ReadRouter interface {
+	CheckReplica(ctx context.Context) (time.Duration, error)
+	UsingReplica() bool
+}
+
+// ReplicaCheckJob periodically checks the read replica so that reports fall
+// back to the primary while it is down or lagging.
+type ReplicaCheckJob struct {
+	readRouter ReadRouter
+	interval   time.Duration
+	logger     *slog.Logger
+}
+
+func NewReplicaCheckJob(readRouter ReadRouter, interval time.Duration, logger *slog.Logger) *ReplicaCheckJob {
+	return &ReplicaCheckJob{
+		readRouter: readRouter,
+		interval:   interval,
+		logger:     logger,
+	}
+}
+
+// Run checks the replica once immediately and then on every tick until ctx is
+// cancelled.
+func (j *ReplicaCheckJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	// Assume the replica was in use so that a failing first check is reported.
+	usingReplica := true
+	for {
+		usingReplica = j.check(ctx, usingReplica)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// check logs only when reads switch between the replica and the primary, and
+// reports where they go now.
+func (j *ReplicaCheckJob) check(ctx context.Context, wasUsingReplica bool) bool {
+	lag, err := j.readRouter.CheckReplica(ctx)
+	usingReplica := j.readRouter.UsingReplica()
+
+	switch {
+	case wasUsingReplica && !usingReplica:
+		j.logger.Warn("reads fall back to the primary", "error", err)
+	case !wasUsingReplica && usingReplica:
+		j.logger.Info("reads go to the replica", "lag", lag.String())
+	case err != nil:
+		j.logger.Debug("replica is still unavailable", "error", err)
+	}
+
+	return usingReplica
+}
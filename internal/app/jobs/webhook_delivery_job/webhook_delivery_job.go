@@ -0,0 +1,70 @@
+package webhook_delivery_job
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/infra/tenant"
+)
+
+type WebhookService interface {
+	DeliverDue(ctx context.Context) (int, error)
+}
+
+// WebhookDeliveryJob polls for due webhook deliveries of every tenant and
+// sends them.
+type WebhookDeliveryJob struct {
+	webhookService WebhookService
+	tenants        []model.Tenant
+	interval       time.Duration
+	logger         *slog.Logger
+}
+
+func NewWebhookDeliveryJob(webhookService WebhookService, tenants []model.Tenant, interval time.Duration, logger *slog.Logger) *WebhookDeliveryJob {
+	return &WebhookDeliveryJob{
+		webhookService: webhookService,
+		tenants:        tenants,
+		interval:       interval,
+		logger:         logger,
+	}
+}
+
+// Run sends due deliveries on every tick until ctx is cancelled. While any
+// tenant had a whole batch due it continues without waiting for the next
+// tick.
+func (j *WebhookDeliveryJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		for j.deliver(ctx) && ctx.Err() == nil {
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// deliver reports whether there may be more due deliveries.
+func (j *WebhookDeliveryJob) deliver(ctx context.Context) bool {
+	more := false
+	for _, t := range j.tenants {
+		logger := j.logger.With("tenant", t.ID)
+		attempted, err := j.webhookService.DeliverDue(tenant.WithTenant(ctx, t))
+		if err != nil {
+			logger.Error("failed to deliver webhooks", "error", err)
+			continue
+		}
+
+		if attempted > 0 {
+			logger.Info("webhook deliveries attempted", "count", attempted)
+			more = true
+		}
+	}
+	return more
+}
@@ -0,0 +1,53 @@
+package app
+
+import (
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/infra/http/middlewares"
+)
+
+var (
+	readers = []model.Role{model.RoleViewer, model.RoleSupport, model.RoleEditor, model.RoleAdmin}
+	// Support staff correct data for users, but only editors add it.
+	creators = []model.Role{model.RoleEditor, model.RoleAdmin}
+	updaters = []model.Role{model.RoleSupport, model.RoleEditor, model.RoleAdmin}
+	admins   = []model.Role{model.RoleAdmin}
+)
+
+// accessPolicy lists the roles allowed to call each API route. Every route
+// registered by bootstrapHandler under /api/ must have an entry. Deleting
+// data is reserved to admins, which keeps support staff from deleting the
+// subscriptions of users.
+var accessPolicy = middlewares.Policy{
+	"POST /api/subscriptions":                       creators,
+	"POST /api/subscriptions:batch":                 creators,
+	"POST /api/subscriptions/import":                creators,
+	"GET /api/subscriptions/{id}":                   readers,
+	"PUT /api/subscriptions/{id}":                   updaters,
+	"DELETE /api/subscriptions/{id}":                admins,
+	"GET /api/subscriptions/{id}/members":           readers,
+	"PUT /api/subscriptions/{id}/members":           updaters,
+	"GET /api/subscriptions":                        readers,
+	"GET /api/subscriptions/export":                 readers,
+	"GET /api/subscriptions/cost":                   readers,
+	"GET /api/subscriptions/duplicates":             readers,
+	"GET /api/subscriptions/anomalies":              readers,
+	"GET /api/users/{user_id}/renewals.ics":         readers,
+	"POST /api/users/{id}/subscription-suggestions": readers,
+
+	"POST /api/budgets":           creators,
+	"GET /api/budgets/{id}":       readers,
+	"PUT /api/budgets/{id}":       updaters,
+	"DELETE /api/budgets/{id}":    admins,
+	"GET /api/users/{id}/budgets": readers,
+	"GET /api/users/{id}/alerts":  readers,
+
+	"POST /api/webhooks":                admins,
+	"GET /api/webhooks":                 admins,
+	"GET /api/webhooks/{id}":            admins,
+	"DELETE /api/webhooks/{id}":         admins,
+	"GET /api/webhooks/{id}/deliveries": admins,
+
+	"POST /api/api-keys":        admins,
+	"GET /api/api-keys":         admins,
+	"DELETE /api/api-keys/{id}": admins,
+}
@@ -0,0 +1,126 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/domain/subscription/service"
+	"github.com/golangtestcases/subscribe-service/internal/infra/tracing"
+	"github.com/google/uuid"
+)
+
+// tracedSubscriptionService records a span named after the method for every
+// call the handlers and jobs make to the subscription service, keeping
+// tracing out of the domain. Methods that do no I/O are passed through.
+type tracedSubscriptionService struct {
+	*service.SubscriptionService
+}
+
+func (s tracedSubscriptionService) CreateSubscription(ctx context.Context, subscription model.Subscription) (result model.Subscription, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionService.CreateSubscription")
+	defer func() { span.RecordError(err); span.End() }()
+	return s.SubscriptionService.CreateSubscription(ctx, subscription)
+}
+
+func (s tracedSubscriptionService) GetSubscriptionByID(ctx context.Context, id uuid.UUID) (result model.Subscription, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionService.GetSubscriptionByID")
+	defer func() { span.RecordError(err); span.End() }()
+	return s.SubscriptionService.GetSubscriptionByID(ctx, id)
+}
+
+func (s tracedSubscriptionService) UpdateSubscription(ctx context.Context, subscription model.Subscription) (err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionService.UpdateSubscription")
+	defer func() { span.RecordError(err); span.End() }()
+	return s.SubscriptionService.UpdateSubscription(ctx, subscription)
+}
+
+func (s tracedSubscriptionService) DeleteSubscription(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionService.DeleteSubscription")
+	defer func() { span.RecordError(err); span.End() }()
+	return s.SubscriptionService.DeleteSubscription(ctx, id)
+}
+
+func (s tracedSubscriptionService) ListSubscriptions(ctx context.Context, limit, offset int) (result []model.Subscription, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionService.ListSubscriptions")
+	defer func() { span.RecordError(err); span.End() }()
+	return s.SubscriptionService.ListSubscriptions(ctx, limit, offset)
+}
+
+func (s tracedSubscriptionService) ListActiveSubscriptions(ctx context.Context, userID uuid.UUID) (result []model.Subscription, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionService.ListActiveSubscriptions")
+	defer func() { span.RecordError(err); span.End() }()
+	return s.SubscriptionService.ListActiveSubscriptions(ctx, userID)
+}
+
+func (s tracedSubscriptionService) GetSubscriptionStats(ctx context.Context) (result model.SubscriptionStats, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionService.GetSubscriptionStats")
+	defer func() { span.RecordError(err); span.End() }()
+	return s.SubscriptionService.GetSubscriptionStats(ctx)
+}
+
+func (s tracedSubscriptionService) ExportSubscriptions(ctx context.Context, limit, offset int, fn func(model.Subscription) error) (err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionService.ExportSubscriptions")
+	defer func() { span.RecordError(err); span.End() }()
+	return s.SubscriptionService.ExportSubscriptions(ctx, limit, offset, fn)
+}
+
+func (s tracedSubscriptionService) GetTotalCost(ctx context.Context, filter model.CostFilter) (result int, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionService.GetTotalCost")
+	defer func() { span.RecordError(err); span.End() }()
+	return s.SubscriptionService.GetTotalCost(ctx, filter)
+}
+
+func (s tracedSubscriptionService) ListSubscriptionMembers(ctx context.Context, subscriptionID uuid.UUID) (result []model.SubscriptionMember, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionService.ListSubscriptionMembers")
+	defer func() { span.RecordError(err); span.End() }()
+	return s.SubscriptionService.ListSubscriptionMembers(ctx, subscriptionID)
+}
+
+func (s tracedSubscriptionService) SetSubscriptionMembers(ctx context.Context, subscriptionID uuid.UUID, members []model.SubscriptionMember) (err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionService.SetSubscriptionMembers")
+	defer func() { span.RecordError(err); span.End() }()
+	return s.SubscriptionService.SetSubscriptionMembers(ctx, subscriptionID, members)
+}
+
+func (s tracedSubscriptionService) ImportSubscriptions(ctx context.Context, subscriptions []model.Subscription, dryRun bool) (result []model.Subscription, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionService.ImportSubscriptions")
+	defer func() { span.RecordError(err); span.End() }()
+	return s.SubscriptionService.ImportSubscriptions(ctx, subscriptions, dryRun)
+}
+
+func (s tracedSubscriptionService) ExecuteBatch(ctx context.Context, operations []model.BatchOperation, atomic bool) (result []model.BatchResult, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionService.ExecuteBatch")
+	defer func() { span.RecordError(err); span.End() }()
+	return s.SubscriptionService.ExecuteBatch(ctx, operations, atomic)
+}
+
+func (s tracedSubscriptionService) FindDuplicates(ctx context.Context, userIDs []uuid.UUID) (result []model.DuplicateGroup, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionService.FindDuplicates")
+	defer func() { span.RecordError(err); span.End() }()
+	return s.SubscriptionService.FindDuplicates(ctx, userIDs)
+}
+
+func (s tracedSubscriptionService) FindDuplicatesOf(ctx context.Context, subscription model.Subscription) (result []model.Subscription, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionService.FindDuplicatesOf")
+	defer func() { span.RecordError(err); span.End() }()
+	return s.SubscriptionService.FindDuplicatesOf(ctx, subscription)
+}
+
+func (s tracedSubscriptionService) FindPriceAnomalies(ctx context.Context, filter model.AnomalyFilter) (result []model.PriceAnomaly, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionService.FindPriceAnomalies")
+	defer func() { span.RecordError(err); span.End() }()
+	return s.SubscriptionService.FindPriceAnomalies(ctx, filter)
+}
+
+func (s tracedSubscriptionService) SuggestSubscriptions(ctx context.Context, userID uuid.UUID, transactions []model.Transaction) (result []model.SuggestedSubscription, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionService.SuggestSubscriptions")
+	defer func() { span.RecordError(err); span.End() }()
+	return s.SubscriptionService.SuggestSubscriptions(ctx, userID, transactions)
+}
+
+func (s tracedSubscriptionService) PublishUpcomingRenewals(ctx context.Context, within time.Duration) (result int, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionService.PublishUpcomingRenewals")
+	defer func() { span.RecordError(err); span.End() }()
+	return s.SubscriptionService.PublishUpcomingRenewals(ctx, within)
+}
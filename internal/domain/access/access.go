@@ -0,0 +1,77 @@
+// Package access carries the identity of the authenticated caller in a
+// context and decides which data the caller may access, for the services that
+// act on its behalf. How callers are authenticated is left to the transport.
+package access
+
+import (
+	"context"
+	"errors"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+// ErrInvalidCredentials is returned by authenticators for credentials that
+// identify no one, such as unknown or revoked keys.
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+type identityKey struct{}
+
+// WithIdentity returns a copy of ctx carrying the caller's identity.
+func WithIdentity(ctx context.Context, identity model.Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// IdentityFromContext returns the identity carried by ctx and whether there is
+// one. Requests are unauthenticated when authentication is turned off.
+func IdentityFromContext(ctx context.Context) (model.Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(model.Identity)
+	return identity, ok
+}
+
+// RestrictedUser returns the only user whose data the caller of ctx may
+// access and true, or false if it may access the data of every user. Contexts
+// without an identity are unrestricted: they come from background jobs or
+// from requests when authentication is turned off.
+func RestrictedUser(ctx context.Context) (uuid.UUID, bool) {
+	identity, ok := IdentityFromContext(ctx)
+	if !ok {
+		return uuid.Nil, false
+	}
+	return identity.RestrictedTo()
+}
+
+// CanAccess reports whether the caller of ctx may access the data of the user.
+func CanAccess(ctx context.Context, userID uuid.UUID) bool {
+	restricted, ok := RestrictedUser(ctx)
+	return !ok || restricted == userID
+}
+
+// CheckAccess returns a model.ForbiddenError if the caller of ctx may not
+// access the data of the user.
+func CheckAccess(ctx context.Context, userID uuid.UUID) error {
+	if !CanAccess(ctx, userID) {
+		return model.NewForbiddenError("the data of other users is not accessible")
+	}
+	return nil
+}
+
+// CheckUnrestricted returns a model.ForbiddenError if the caller of ctx is
+// restricted to its own data, for operations spanning all users.
+func CheckUnrestricted(ctx context.Context) error {
+	if _, ok := RestrictedUser(ctx); ok {
+		return model.NewForbiddenError("the operation spans all users and requires the admin role")
+	}
+	return nil
+}
+
+// CheckRole returns a model.ForbiddenError if the caller of ctx does not hold
+// the role, for operations whose route admits more roles than they do.
+// Contexts without an identity pass, as in RestrictedUser.
+func CheckRole(ctx context.Context, role model.Role) error {
+	identity, ok := IdentityFromContext(ctx)
+	if ok && !identity.HasRole(role) {
+		return model.NewForbiddenError("the operation requires the %s role", role)
+	}
+	return nil
+}
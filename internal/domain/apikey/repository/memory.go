@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/infra/tenant"
+	"github.com/google/uuid"
+)
+
+// InMemoryRepository keeps API keys in memory with the same semantics as
+// PostgreSQLRepository. It is safe for concurrent use.
+type InMemoryRepository struct {
+	mu      sync.Mutex
+	apiKeys []model.APIKey
+}
+
+func NewInMemoryRepository() *InMemoryRepository {
+	return &InMemoryRepository{}
+}
+
+func (r *InMemoryRepository) CreateAPIKey(ctx context.Context, apiKey model.APIKey) (model.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	apiKey.ID = uuid.New()
+	apiKey.TenantID = tenant.ID(ctx)
+	apiKey.CreatedAt = time.Now()
+	apiKey.Roles = slices.Clone(apiKey.Roles)
+	r.apiKeys = append(r.apiKeys, apiKey)
+
+	return copyAPIKey(apiKey), nil
+}
+
+func (r *InMemoryRepository) GetAPIKeyByHash(_ context.Context, keyHash string) (model.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, apiKey := range r.apiKeys {
+		if apiKey.KeyHash == keyHash {
+			return copyAPIKey(apiKey), nil
+		}
+	}
+	return model.APIKey{}, sql.ErrNoRows
+}
+
+func (r *InMemoryRepository) ListAPIKeys(ctx context.Context) ([]model.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var apiKeys []model.APIKey
+	for _, apiKey := range r.apiKeys {
+		if apiKey.TenantID == tenant.ID(ctx) {
+			apiKeys = append(apiKeys, copyAPIKey(apiKey))
+		}
+	}
+	return apiKeys, nil
+}
+
+func (r *InMemoryRepository) RevokeAPIKey(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, apiKey := range r.apiKeys {
+		if apiKey.ID == id && apiKey.TenantID == tenant.ID(ctx) && apiKey.RevokedAt == nil {
+			now := time.Now()
+			r.apiKeys[i].RevokedAt = &now
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+// copyAPIKey keeps callers from changing stored keys through shared slices
+// and pointers.
+func copyAPIKey(apiKey model.APIKey) model.APIKey {
+	apiKey.Roles = slices.Clone(apiKey.Roles)
+	if apiKey.RevokedAt != nil {
+		revokedAt := *apiKey.RevokedAt
+		apiKey.RevokedAt = &revokedAt
+	}
+	return apiKey
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/infra/database"
+	"github.com/golangtestcases/subscribe-service/internal/infra/tenant"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// PostgreSQLRepository scopes every query to the tenant of its context, except
+// GetAPIKeyByHash: the key tells which tenant a request is for.
+type PostgreSQLRepository struct {
+	db *sql.DB
+}
+
+func NewPostgreSQLRepository(db *sql.DB) *PostgreSQLRepository {
+	return &PostgreSQLRepository{db: db}
+}
+
+// conn returns the transaction carried by ctx, if any, or the connection pool.
+func (r *PostgreSQLRepository) conn(ctx context.Context) database.Querier {
+	return database.Conn(ctx, r.db)
+}
+
+func (r *PostgreSQLRepository) CreateAPIKey(ctx context.Context, apiKey model.APIKey) (model.APIKey, error) {
+	apiKey.ID = uuid.New()
+	apiKey.TenantID = tenant.ID(ctx)
+	apiKey.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO api_keys (id, tenant_id, name, prefix, key_hash, roles, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := r.conn(ctx).ExecContext(ctx, query,
+		apiKey.ID, apiKey.TenantID, apiKey.Name, apiKey.Prefix, apiKey.KeyHash, rolesArray(apiKey.Roles), apiKey.CreatedAt,
+	)
+
+	return apiKey, err
+}
+
+// GetAPIKeyByHash returns the key with the hash, revoked or not, in whatever
+// tenant it was issued.
+func (r *PostgreSQLRepository) GetAPIKeyByHash(ctx context.Context, keyHash string) (model.APIKey, error) {
+	query := `
+		SELECT id, tenant_id, name, prefix, key_hash, roles, created_at, revoked_at
+		FROM api_keys WHERE key_hash = $1
+	`
+
+	return scanAPIKey(r.conn(ctx).QueryRowContext(ctx, query, keyHash))
+}
+
+func (r *PostgreSQLRepository) ListAPIKeys(ctx context.Context) ([]model.APIKey, error) {
+	query := `
+		SELECT id, tenant_id, name, prefix, key_hash, roles, created_at, revoked_at
+		FROM api_keys WHERE tenant_id = $1 ORDER BY created_at
+	`
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, tenant.ID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var apiKeys []model.APIKey
+	for rows.Next() {
+		apiKey, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		apiKeys = append(apiKeys, apiKey)
+	}
+
+	return apiKeys, rows.Err()
+}
+
+// RevokeAPIKey marks the key as revoked. A missing or already revoked key is
+// reported as sql.ErrNoRows.
+func (r *PostgreSQLRepository) RevokeAPIKey(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE api_keys SET revoked_at = $3 WHERE tenant_id = $1 AND id = $2 AND revoked_at IS NULL`
+	result, err := r.conn(ctx).ExecContext(ctx, query, tenant.ID(ctx), id, time.Now())
+	if err != nil {
+		return err
+	}
+
+	return checkRowsAffected(result)
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanAPIKey(row scanner) (model.APIKey, error) {
+	var apiKey model.APIKey
+	var roles pq.StringArray
+	err := row.Scan(
+		&apiKey.ID, &apiKey.TenantID, &apiKey.Name, &apiKey.Prefix, &apiKey.KeyHash, &roles,
+		&apiKey.CreatedAt, &apiKey.RevokedAt,
+	)
+	if err != nil {
+		return model.APIKey{}, err
+	}
+
+	apiKey.Roles = toRoles(roles)
+	return apiKey, nil
+}
+
+func rolesArray(roles []model.Role) pq.StringArray {
+	array := make(pq.StringArray, 0, len(roles))
+	for _, role := range roles {
+		array = append(array, string(role))
+	}
+	return array
+}
+
+func toRoles(values []string) []model.Role {
+	roles := make([]model.Role, 0, len(values))
+	for _, value := range values {
+		roles = append(roles, model.Role(value))
+	}
+	return roles
+}
+
+func checkRowsAffected(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
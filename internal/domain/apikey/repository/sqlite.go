@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/infra/database"
+	"github.com/golangtestcases/subscribe-service/internal/infra/tenant"
+	"github.com/google/uuid"
+)
+
+// sqliteTimeLayout matches the layout of the subscription repository, so that
+// all tables of the database store times alike.
+const sqliteTimeLayout = "2006-01-02 15:04:05.000000"
+
+// SQLiteRepository stores API keys in SQLite with the same semantics as
+// PostgreSQLRepository. Roles are kept as comma-separated text.
+type SQLiteRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteRepository(db *sql.DB) *SQLiteRepository {
+	return &SQLiteRepository{db: db}
+}
+
+// conn returns the transaction carried by ctx, if any, or the connection pool.
+func (r *SQLiteRepository) conn(ctx context.Context) database.Querier {
+	return database.Conn(ctx, r.db)
+}
+
+func (r *SQLiteRepository) CreateAPIKey(ctx context.Context, apiKey model.APIKey) (model.APIKey, error) {
+	apiKey.ID = uuid.New()
+	apiKey.TenantID = tenant.ID(ctx)
+	apiKey.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO api_keys (id, tenant_id, name, prefix, key_hash, roles, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.conn(ctx).ExecContext(ctx, query,
+		apiKey.ID, apiKey.TenantID, apiKey.Name, apiKey.Prefix, apiKey.KeyHash, joinRoles(apiKey.Roles), sqliteTime(apiKey.CreatedAt),
+	)
+
+	return apiKey, err
+}
+
+func (r *SQLiteRepository) GetAPIKeyByHash(ctx context.Context, keyHash string) (model.APIKey, error) {
+	query := `
+		SELECT id, tenant_id, name, prefix, key_hash, roles, created_at, revoked_at
+		FROM api_keys WHERE key_hash = ?
+	`
+
+	return scanSQLiteAPIKey(r.conn(ctx).QueryRowContext(ctx, query, keyHash))
+}
+
+func (r *SQLiteRepository) ListAPIKeys(ctx context.Context) ([]model.APIKey, error) {
+	query := `
+		SELECT id, tenant_id, name, prefix, key_hash, roles, created_at, revoked_at
+		FROM api_keys WHERE tenant_id = ? ORDER BY created_at
+	`
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, tenant.ID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var apiKeys []model.APIKey
+	for rows.Next() {
+		apiKey, err := scanSQLiteAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		apiKeys = append(apiKeys, apiKey)
+	}
+
+	return apiKeys, rows.Err()
+}
+
+func (r *SQLiteRepository) RevokeAPIKey(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE api_keys SET revoked_at = ? WHERE tenant_id = ? AND id = ? AND revoked_at IS NULL`
+	result, err := r.conn(ctx).ExecContext(ctx, query, sqliteTime(time.Now()), tenant.ID(ctx), id)
+	if err != nil {
+		return err
+	}
+
+	return checkRowsAffected(result)
+}
+
+func scanSQLiteAPIKey(row scanner) (model.APIKey, error) {
+	var apiKey model.APIKey
+	var roles string
+	err := row.Scan(
+		&apiKey.ID, &apiKey.TenantID, &apiKey.Name, &apiKey.Prefix, &apiKey.KeyHash, &roles,
+		&apiKey.CreatedAt, &apiKey.RevokedAt,
+	)
+	if err != nil {
+		return model.APIKey{}, err
+	}
+
+	apiKey.Roles = toRoles(strings.Split(roles, ","))
+	return apiKey, nil
+}
+
+func joinRoles(roles []model.Role) string {
+	values := make([]string, 0, len(roles))
+	for _, role := range roles {
+		values = append(values, string(role))
+	}
+	return strings.Join(values, ",")
+}
+
+func sqliteTime(t time.Time) string {
+	return t.UTC().Format(sqliteTimeLayout)
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/access"
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+const (
+	// keyPrefix starts every issued key, so that leaked keys are easy to
+	// recognise in logs and by secret scanners.
+	keyPrefix = "ssk_"
+	// shownPrefixLength is how much of a key is kept in clear to identify it.
+	shownPrefixLength = len(keyPrefix) + 8
+	maxNameLength     = 255
+
+	// AdminKeySubject identifies callers using the configured admin key.
+	AdminKeySubject = "admin_key"
+)
+
+type APIKeyRepository interface {
+	CreateAPIKey(context.Context, model.APIKey) (model.APIKey, error)
+	GetAPIKeyByHash(context.Context, string) (model.APIKey, error)
+	ListAPIKeys(context.Context) ([]model.APIKey, error)
+	RevokeAPIKey(context.Context, uuid.UUID) error
+}
+
+type APIKeyService struct {
+	apiKeyRepository APIKeyRepository
+	adminKeyHash     string
+}
+
+// NewAPIKeyService returns a service accepting the keys in the repository and,
+// if adminKey is not empty, adminKey itself with the admin role in every
+// tenant. The admin key cannot be revoked; it exists to issue the first keys.
+func NewAPIKeyService(apiKeyRepository APIKeyRepository, adminKey string) *APIKeyService {
+	s := &APIKeyService{apiKeyRepository: apiKeyRepository}
+	if adminKey != "" {
+		s.adminKeyHash = hashKey(adminKey)
+	}
+	return s
+}
+
+// IssueAPIKey creates a key with the roles in the tenant of ctx and returns it
+// along with the key itself, which is not stored and cannot be shown again.
+func (s *APIKeyService) IssueAPIKey(ctx context.Context, name string, roles []model.Role) (model.APIKey, string, error) {
+	if err := validateAPIKey(name, roles); err != nil {
+		return model.APIKey{}, "", err
+	}
+
+	key, err := generateKey()
+	if err != nil {
+		return model.APIKey{}, "", fmt.Errorf("generateKey: %w", err)
+	}
+
+	apiKey, err := s.apiKeyRepository.CreateAPIKey(ctx, model.APIKey{
+		Name:    name,
+		Prefix:  key[:shownPrefixLength],
+		KeyHash: hashKey(key),
+		Roles:   slices.Compact(slices.Sorted(slices.Values(roles))),
+	})
+	if err != nil {
+		return model.APIKey{}, "", fmt.Errorf("apiKeyRepository.CreateAPIKey: %w", err)
+	}
+
+	return apiKey, key, nil
+}
+
+func (s *APIKeyService) ListAPIKeys(ctx context.Context) ([]model.APIKey, error) {
+	apiKeys, err := s.apiKeyRepository.ListAPIKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("apiKeyRepository.ListAPIKeys: %w", err)
+	}
+
+	return apiKeys, nil
+}
+
+func (s *APIKeyService) RevokeAPIKey(ctx context.Context, id uuid.UUID) error {
+	if id == uuid.Nil {
+		return model.NewValidationError("id is required")
+	}
+
+	if err := s.apiKeyRepository.RevokeAPIKey(ctx, id); err != nil {
+		return fmt.Errorf("apiKeyRepository.RevokeAPIKey: %w", err)
+	}
+
+	return nil
+}
+
+// Authenticate returns the identity of the caller presenting key, bound to the
+// tenant the key was issued in, or access.ErrInvalidCredentials if the key is
+// unknown or revoked.
+func (s *APIKeyService) Authenticate(ctx context.Context, key string) (model.Identity, error) {
+	keyHash := hashKey(key)
+
+	if s.adminKeyHash != "" && subtle.ConstantTimeCompare([]byte(keyHash), []byte(s.adminKeyHash)) == 1 {
+		return model.Identity{Subject: AdminKeySubject, Roles: []model.Role{model.RoleAdmin}}, nil
+	}
+
+	apiKey, err := s.apiKeyRepository.GetAPIKeyByHash(ctx, keyHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Identity{}, access.ErrInvalidCredentials
+		}
+		return model.Identity{}, fmt.Errorf("apiKeyRepository.GetAPIKeyByHash: %w", err)
+	}
+	if apiKey.RevokedAt != nil {
+		return model.Identity{}, access.ErrInvalidCredentials
+	}
+
+	return model.Identity{Subject: "api_key:" + apiKey.ID.String(), Roles: apiKey.Roles, TenantID: apiKey.TenantID}, nil
+}
+
+func validateAPIKey(name string, roles []model.Role) error {
+	if name == "" {
+		return model.NewValidationError("name is required")
+	}
+	if len(name) > maxNameLength {
+		return model.NewValidationError("name must be at most %d characters", maxNameLength)
+	}
+	if len(roles) == 0 {
+		return model.NewValidationError("roles is required")
+	}
+	for _, role := range roles {
+		if !slices.Contains(model.Roles, role) {
+			return model.NewValidationError("unknown role %q", role)
+		}
+	}
+	return nil
+}
+
+func generateKey() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return keyPrefix + base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// hashKey returns the SHA-256 of the key in hex. Keys are random, so a fast
+// unsalted hash is enough to make a leaked table useless.
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
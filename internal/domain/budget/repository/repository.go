@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/infra/database"
+	"github.com/golangtestcases/subscribe-service/internal/infra/tenant"
+	"github.com/google/uuid"
+	_ "github.com/lib/pq"
+)
+
+// PostgreSQLRepository scopes every query to the tenant of its context.
+type PostgreSQLRepository struct {
+	db *sql.DB
+}
+
+func NewPostgreSQLRepository(db *sql.DB) *PostgreSQLRepository {
+	return &PostgreSQLRepository{db: db}
+}
+
+// conn returns the transaction carried by ctx, if any, or the connection pool.
+func (r *PostgreSQLRepository) conn(ctx context.Context) database.Querier {
+	return database.Conn(ctx, r.db)
+}
+
+func (r *PostgreSQLRepository) CreateBudget(ctx context.Context, budget model.Budget) (model.Budget, error) {
+	budget.ID = uuid.New()
+	budget.CreatedAt = time.Now()
+	budget.UpdatedAt = time.Now()
+
+	query := `
+		INSERT INTO budgets (id, tenant_id, user_id, service_name, amount, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := r.conn(ctx).ExecContext(ctx, query,
+		budget.ID, tenant.ID(ctx), budget.UserID, budget.ServiceName, budget.Amount,
+		budget.CreatedAt, budget.UpdatedAt,
+	)
+
+	return budget, err
+}
+
+func (r *PostgreSQLRepository) GetBudgetByID(ctx context.Context, id uuid.UUID) (model.Budget, error) {
+	var budget model.Budget
+	query := `
+		SELECT id, user_id, service_name, amount, created_at, updated_at
+		FROM budgets WHERE tenant_id = $1 AND id = $2
+	`
+
+	err := r.conn(ctx).QueryRowContext(ctx, query, tenant.ID(ctx), id).Scan(
+		&budget.ID, &budget.UserID, &budget.ServiceName, &budget.Amount,
+		&budget.CreatedAt, &budget.UpdatedAt,
+	)
+
+	return budget, err
+}
+
+func (r *PostgreSQLRepository) UpdateBudget(ctx context.Context, budget model.Budget) error {
+	budget.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE budgets
+		SET user_id = $3, service_name = $4, amount = $5, updated_at = $6
+		WHERE tenant_id = $1 AND id = $2
+	`
+
+	result, err := r.conn(ctx).ExecContext(ctx, query,
+		tenant.ID(ctx), budget.ID, budget.UserID, budget.ServiceName, budget.Amount, budget.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return checkRowsAffected(result)
+}
+
+func (r *PostgreSQLRepository) DeleteBudget(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM budgets WHERE tenant_id = $1 AND id = $2`
+	result, err := r.conn(ctx).ExecContext(ctx, query, tenant.ID(ctx), id)
+	if err != nil {
+		return err
+	}
+
+	return checkRowsAffected(result)
+}
+
+func (r *PostgreSQLRepository) ListBudgets(ctx context.Context, userID uuid.UUID) ([]model.Budget, error) {
+	query := `
+		SELECT id, user_id, service_name, amount, created_at, updated_at
+		FROM budgets WHERE tenant_id = $1 AND user_id = $2 ORDER BY created_at DESC
+	`
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, tenant.ID(ctx), userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var budgets []model.Budget
+	for rows.Next() {
+		var budget model.Budget
+		err := rows.Scan(
+			&budget.ID, &budget.UserID, &budget.ServiceName, &budget.Amount,
+			&budget.CreatedAt, &budget.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		budgets = append(budgets, budget)
+	}
+
+	return budgets, rows.Err()
+}
+
+// GetMonthlySpend sums what the user pays for subscriptions active in the given
+// month: their share of shared subscriptions and the full price of the rest.
+// A nil serviceName means all services.
+func (r *PostgreSQLRepository) GetMonthlySpend(ctx context.Context, userID uuid.UUID, serviceName *string, month time.Time) (int, error) {
+	query := `
+		SELECT COALESCE(ROUND(SUM(s.price * COALESCE(m.share, 1))), 0)::INTEGER
+		FROM subscriptions s
+		LEFT JOIN subscription_members m ON m.subscription_id = s.id AND m.user_id = $2
+		WHERE s.tenant_id = $1 AND (m.user_id IS NOT NULL OR (s.user_id = $2 AND NOT EXISTS (
+				SELECT 1 FROM subscription_members sm WHERE sm.subscription_id = s.id
+			)))
+			AND s.start_date <= $3 AND (s.end_date IS NULL OR s.end_date >= $3)
+			AND ($4::VARCHAR IS NULL OR LOWER(s.service_name) = LOWER($4))
+	`
+
+	var spend int
+	err := r.conn(ctx).QueryRowContext(ctx, query, tenant.ID(ctx), userID, month, serviceName).Scan(&spend)
+	return spend, err
+}
+
+// CreateAlert records the alert unless one already exists for the same budget,
+// threshold and month. The returned flag reports whether a new row was written.
+func (r *PostgreSQLRepository) CreateAlert(ctx context.Context, alert model.BudgetAlert) (bool, error) {
+	alert.ID = uuid.New()
+	alert.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO budget_alerts (id, tenant_id, budget_id, user_id, threshold, spend, amount, month, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (budget_id, threshold, month) DO NOTHING
+	`
+
+	result, err := r.conn(ctx).ExecContext(ctx, query,
+		alert.ID, tenant.ID(ctx), alert.BudgetID, alert.UserID, alert.Threshold,
+		alert.Spend, alert.Amount, alert.Month, alert.CreatedAt,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+func (r *PostgreSQLRepository) ListAlerts(ctx context.Context, userID uuid.UUID) ([]model.BudgetAlert, error) {
+	query := `
+		SELECT id, budget_id, user_id, threshold, spend, amount, month, created_at
+		FROM budget_alerts WHERE tenant_id = $1 AND user_id = $2 ORDER BY created_at DESC
+	`
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, tenant.ID(ctx), userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var alerts []model.BudgetAlert
+	for rows.Next() {
+		var alert model.BudgetAlert
+		err := rows.Scan(
+			&alert.ID, &alert.BudgetID, &alert.UserID, &alert.Threshold,
+			&alert.Spend, &alert.Amount, &alert.Month, &alert.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, rows.Err()
+}
+
+func checkRowsAffected(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/access"
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+// alertThresholds are the percentages of a budget at which an alert is recorded.
+var alertThresholds = []int{80, 100}
+
+type BudgetRepository interface {
+	CreateBudget(context.Context, model.Budget) (model.Budget, error)
+	GetBudgetByID(context.Context, uuid.UUID) (model.Budget, error)
+	UpdateBudget(context.Context, model.Budget) error
+	DeleteBudget(context.Context, uuid.UUID) error
+	ListBudgets(context.Context, uuid.UUID) ([]model.Budget, error)
+	GetMonthlySpend(context.Context, uuid.UUID, *string, time.Time) (int, error)
+	CreateAlert(context.Context, model.BudgetAlert) (bool, error)
+	ListAlerts(context.Context, uuid.UUID) ([]model.BudgetAlert, error)
+}
+
+type BudgetService struct {
+	budgetRepository BudgetRepository
+}
+
+func NewBudgetService(budgetRepository BudgetRepository) *BudgetService {
+	return &BudgetService{budgetRepository: budgetRepository}
+}
+
+func (s *BudgetService) CreateBudget(ctx context.Context, budget model.Budget) (model.Budget, error) {
+	if err := validateBudget(budget); err != nil {
+		return model.Budget{}, err
+	}
+	if err := access.CheckAccess(ctx, budget.UserID); err != nil {
+		return model.Budget{}, err
+	}
+
+	newBudget, err := s.budgetRepository.CreateBudget(ctx, budget)
+	if err != nil {
+		return model.Budget{}, fmt.Errorf("budgetRepository.CreateBudget: %w", err)
+	}
+
+	return newBudget, nil
+}
+
+// GetBudgetByID returns the budget. Budgets of other users are reported as
+// sql.ErrNoRows to callers restricted to their own data.
+func (s *BudgetService) GetBudgetByID(ctx context.Context, id uuid.UUID) (model.Budget, error) {
+	if id == uuid.Nil {
+		return model.Budget{}, model.NewValidationError("id is required")
+	}
+
+	budget, err := s.budgetRepository.GetBudgetByID(ctx, id)
+	if err == nil && !access.CanAccess(ctx, budget.UserID) {
+		err = sql.ErrNoRows
+	}
+	if err != nil {
+		return model.Budget{}, fmt.Errorf("budgetRepository.GetBudgetByID: %w", err)
+	}
+
+	return budget, nil
+}
+
+func (s *BudgetService) UpdateBudget(ctx context.Context, budget model.Budget) error {
+	if budget.ID == uuid.Nil {
+		return model.NewValidationError("id is required")
+	}
+	if err := validateBudget(budget); err != nil {
+		return err
+	}
+	if err := access.CheckAccess(ctx, budget.UserID); err != nil {
+		return err
+	}
+	if err := s.checkOwner(ctx, budget.ID); err != nil {
+		return err
+	}
+
+	err := s.budgetRepository.UpdateBudget(ctx, budget)
+	if err != nil {
+		return fmt.Errorf("budgetRepository.UpdateBudget: %w", err)
+	}
+
+	return nil
+}
+
+func (s *BudgetService) DeleteBudget(ctx context.Context, id uuid.UUID) error {
+	if id == uuid.Nil {
+		return model.NewValidationError("id is required")
+	}
+	if err := s.checkOwner(ctx, id); err != nil {
+		return err
+	}
+
+	err := s.budgetRepository.DeleteBudget(ctx, id)
+	if err != nil {
+		return fmt.Errorf("budgetRepository.DeleteBudget: %w", err)
+	}
+
+	return nil
+}
+
+func (s *BudgetService) ListBudgets(ctx context.Context, userID uuid.UUID) ([]model.Budget, error) {
+	if userID == uuid.Nil {
+		return nil, model.NewValidationError("user_id is required")
+	}
+	if err := access.CheckAccess(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	budgets, err := s.budgetRepository.ListBudgets(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("budgetRepository.ListBudgets: %w", err)
+	}
+
+	return budgets, nil
+}
+
+func (s *BudgetService) ListAlerts(ctx context.Context, userID uuid.UUID) ([]model.BudgetAlert, error) {
+	if userID == uuid.Nil {
+		return nil, model.NewValidationError("user_id is required")
+	}
+	if err := access.CheckAccess(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	alerts, err := s.budgetRepository.ListAlerts(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("budgetRepository.ListAlerts: %w", err)
+	}
+
+	return alerts, nil
+}
+
+// EvaluateBudgets compares the user's projected spend for the current month
+// with each of their budgets and records an alert for every threshold crossed.
+func (s *BudgetService) EvaluateBudgets(ctx context.Context, userID uuid.UUID) error {
+	budgets, err := s.budgetRepository.ListBudgets(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("budgetRepository.ListBudgets: %w", err)
+	}
+
+	now := time.Now().UTC()
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	for _, budget := range budgets {
+		spend, err := s.budgetRepository.GetMonthlySpend(ctx, userID, budget.ServiceName, month)
+		if err != nil {
+			return fmt.Errorf("budgetRepository.GetMonthlySpend: %w", err)
+		}
+
+		for _, threshold := range alertThresholds {
+			if spend*100 < budget.Amount*threshold {
+				continue
+			}
+
+			_, err := s.budgetRepository.CreateAlert(ctx, model.BudgetAlert{
+				BudgetID:  budget.ID,
+				UserID:    userID,
+				Threshold: threshold,
+				Spend:     spend,
+				Amount:    budget.Amount,
+				Month:     month,
+			})
+			if err != nil {
+				return fmt.Errorf("budgetRepository.CreateAlert: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkOwner returns sql.ErrNoRows if the budget belongs to another user than
+// the one the caller is restricted to, as GetBudgetByID does.
+func (s *BudgetService) checkOwner(ctx context.Context, id uuid.UUID) error {
+	if _, ok := access.RestrictedUser(ctx); !ok {
+		return nil
+	}
+
+	_, err := s.GetBudgetByID(ctx, id)
+	return err
+}
+
+func validateBudget(budget model.Budget) error {
+	if budget.UserID == uuid.Nil {
+		return model.NewValidationError("user_id is required")
+	}
+	if budget.ServiceName != nil && *budget.ServiceName == "" {
+		return model.NewValidationError("service_name must not be empty")
+	}
+	if budget.Amount <= 0 {
+		return model.NewValidationError("amount must be positive")
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package model
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Role is what a caller is allowed to do. Each API route lists the roles
+// allowed to call it in the access policy of the app.
+type Role string
+
+const (
+	// RoleViewer reads data.
+	RoleViewer Role = "viewer"
+	// RoleSupport reads data and corrects it for users, but never deletes
+	// it.
+	RoleSupport Role = "support"
+	// RoleEditor reads, creates and updates data.
+	RoleEditor Role = "editor"
+	// RoleAdmin may do everything, including deleting data and managing
+	// webhooks and API keys.
+	RoleAdmin Role = "admin"
+)
+
+var Roles = []Role{RoleViewer, RoleSupport, RoleEditor, RoleAdmin}
+
+// APIKey is an issued key. Only the SHA-256 hash of the key is stored; Prefix
+// keeps its first characters so that people can tell their keys apart. A key
+// acts only for the tenant it was issued in.
+type APIKey struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	TenantID  string     `json:"tenant_id" db:"tenant_id"`
+	Name      string     `json:"name" db:"name"`
+	Prefix    string     `json:"prefix" db:"prefix"`
+	KeyHash   string     `json:"-" db:"key_hash"`
+	Roles     []Role     `json:"roles" db:"roles"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+}
+
+// Identity is the authenticated caller of a request.
+type Identity struct {
+	// Subject names the caller in logs and audit records, such as
+	// "api_key:<id>".
+	Subject string
+	Roles   []Role
+	// UserID is the end user a token was issued to. It is uuid.Nil for API
+	// keys, which act for a service rather than a user.
+	UserID uuid.UUID
+	// TenantID is the tenant the credential belongs to, which the caller
+	// cannot leave. It is empty only for the admin key, which is valid in
+	// every tenant.
+	TenantID string
+}
+
+// HasRole reports whether the caller holds any of the roles.
+func (i Identity) HasRole(roles ...Role) bool {
+	return slices.ContainsFunc(i.Roles, func(held Role) bool {
+		return slices.Contains(roles, held)
+	})
+}
+
+// RestrictedTo returns the only user whose data the caller may access and
+// true, or false if it may access the data of every user. End users are
+// restricted to their own data unless they hold the admin role.
+func (i Identity) RestrictedTo() (uuid.UUID, bool) {
+	if i.UserID == uuid.Nil || i.HasRole(RoleAdmin) {
+		return uuid.Nil, false
+	}
+	return i.UserID, true
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// ErrBatchRolledBack is returned for an atomic batch in which at least one
+// operation failed, so nothing was written.
+var ErrBatchRolledBack = errors.New("batch rolled back")
+
+type BatchOperationType string
+
+const (
+	BatchOperationCreate BatchOperationType = "create"
+	BatchOperationUpdate BatchOperationType = "update"
+	BatchOperationDelete BatchOperationType = "delete"
+)
+
+type BatchStatus string
+
+const (
+	BatchStatusSucceeded  BatchStatus = "succeeded"
+	BatchStatusFailed     BatchStatus = "failed"
+	BatchStatusRolledBack BatchStatus = "rolled_back"
+	BatchStatusSkipped    BatchStatus = "skipped"
+)
+
+// BatchOperation is one item of a batch request. Subscription is used by create
+// and update, ID by update and delete.
+type BatchOperation struct {
+	Type         BatchOperationType
+	ID           uuid.UUID
+	Subscription Subscription
+}
+
+type BatchResult struct {
+	Type         BatchOperationType
+	Status       BatchStatus
+	Subscription Subscription
+	Err          error
+}
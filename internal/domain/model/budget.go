@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Budget struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	UserID      uuid.UUID `json:"user_id" db:"user_id"`
+	ServiceName *string   `json:"service_name,omitempty" db:"service_name"`
+	Amount      int       `json:"amount" db:"amount"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type BudgetAlert struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	BudgetID  uuid.UUID `json:"budget_id" db:"budget_id"`
+	UserID    uuid.UUID `json:"user_id" db:"user_id"`
+	Threshold int       `json:"threshold" db:"threshold"`
+	Spend     int       `json:"spend" db:"spend"`
+	Amount    int       `json:"amount" db:"amount"`
+	Month     time.Time `json:"month" db:"month"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
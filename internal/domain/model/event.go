@@ -0,0 +1,52 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type EventType string
+
+const (
+	EventSubscriptionCreated EventType = "subscription.created"
+	EventSubscriptionUpdated EventType = "subscription.updated"
+	EventSubscriptionDeleted EventType = "subscription.deleted"
+	EventRenewalUpcoming     EventType = "renewal.upcoming"
+)
+
+// EventTypes lists every event integrators can subscribe to.
+var EventTypes = []EventType{
+	EventSubscriptionCreated,
+	EventSubscriptionUpdated,
+	EventSubscriptionDeleted,
+	EventRenewalUpcoming,
+}
+
+// Event is a domain event about a subscription. Payload is the JSON body
+// delivered to integrators. TenantID is the tenant the event happened in; it
+// is filled in when the event is read back from the outbox.
+type Event struct {
+	ID         uuid.UUID       `json:"id"`
+	TenantID   string          `json:"tenant_id"`
+	Type       EventType       `json:"type"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Payload    json.RawMessage `json:"data"`
+}
+
+// RenewalEventData is the payload of renewal.upcoming.
+type RenewalEventData struct {
+	Subscription Subscription `json:"subscription"`
+	RenewalDate  time.Time    `json:"renewal_date"`
+}
+
+// OutboxEntry is an event stored in the outbox together with the state of its
+// publication to the sinks.
+type OutboxEntry struct {
+	Event
+	Attempts      int
+	LastError     *string
+	NextAttemptAt time.Time
+	PublishedAt   *time.Time
+}
@@ -0,0 +1,18 @@
+package model
+
+import "fmt"
+
+// ForbiddenError is a request the caller is not allowed to make, such as
+// reading the data of another user. Handlers answer it with 403 and its
+// message.
+type ForbiddenError struct {
+	Message string
+}
+
+func NewForbiddenError(format string, args ...any) *ForbiddenError {
+	return &ForbiddenError{Message: fmt.Sprintf(format, args...)}
+}
+
+func (e *ForbiddenError) Error() string {
+	return e.Message
+}
@@ -0,0 +1,29 @@
+package model
+
+// DependencyHealth is the result of checking one dependency of the service.
+// An optional dependency that fails does not make the service unready.
+type DependencyHealth struct {
+	Name     string
+	Optional bool
+	Error    error
+}
+
+// Readiness tells whether the service can take traffic.
+type Readiness struct {
+	ShuttingDown bool
+	Dependencies []DependencyHealth
+}
+
+// Ready reports whether the service is not shutting down and every required
+// dependency works.
+func (r Readiness) Ready() bool {
+	if r.ShuttingDown {
+		return false
+	}
+	for _, dependency := range r.Dependencies {
+		if dependency.Error != nil && !dependency.Optional {
+			return false
+		}
+	}
+	return true
+}
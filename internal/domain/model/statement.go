@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// Transaction is one line of a bank statement. Amount is negative for charges.
+type Transaction struct {
+	Date        time.Time
+	Description string
+	Amount      float64
+}
+
+type BillingCadence string
+
+const (
+	BillingCadenceMonthly BillingCadence = "monthly"
+	BillingCadenceYearly  BillingCadence = "yearly"
+)
+
+// SuggestedSubscription is a recurring charge found in a bank statement. Its
+// Subscription is ready to be passed to CreateSubscription; for yearly charges
+// Price is the monthly equivalent and ChargeAmount the amount actually billed.
+type SuggestedSubscription struct {
+	Subscription   Subscription
+	Merchant       string
+	Cadence        BillingCadence
+	ChargeAmount   int
+	Occurrences    int
+	LastChargeDate time.Time
+	Confidence     float64
+	AlreadyTracked bool
+}
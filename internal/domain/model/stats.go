@@ -0,0 +1,9 @@
+package model
+
+// SubscriptionStats summarises the subscriptions active at a moment.
+// MonthlySpend is the sum of their monthly prices.
+type SubscriptionStats struct {
+	ActiveSubscriptions int
+	ActiveUsers         int
+	MonthlySpend        int
+}
@@ -21,4 +21,48 @@ type CostFilter struct {
 	ServiceName *string
 	StartDate   *time.Time
 	EndDate     *time.Time
-}
\ No newline at end of file
+}
+
+// DuplicateGroup is a set of overlapping active subscriptions to the same
+// canonical service. WastedSpend is what would be saved by keeping only the
+// most expensive one.
+type DuplicateGroup struct {
+	ServiceName   string
+	UserIDs       []uuid.UUID
+	Subscriptions []Subscription
+	MonthlySpend  int
+	WastedSpend   int
+}
+
+// PriceStatistics describes the prices paid for one canonical service by
+// subscriptions active in the same period.
+type PriceStatistics struct {
+	ServiceName string
+	Count       int
+	Median      int
+	Min         int
+	Max         int
+}
+
+// PriceAnomaly is a subscription whose price deviates from the typical price
+// of its service by more than the requested share.
+type PriceAnomaly struct {
+	Subscription Subscription
+	Statistics   PriceStatistics
+	Deviation    float64
+}
+
+type AnomalyFilter struct {
+	Period     time.Time
+	Threshold  float64
+	MinSamples int
+}
+
+// SubscriptionMember is a user sharing a subscription paid by someone else.
+// Share is the member's part of the price; the shares of all members of a
+// subscription add up to 1.
+type SubscriptionMember struct {
+	SubscriptionID uuid.UUID `json:"subscription_id" db:"subscription_id"`
+	UserID         uuid.UUID `json:"user_id" db:"user_id"`
+	Share          float64   `json:"share" db:"share"`
+}
@@ -0,0 +1,10 @@
+package model
+
+// Tenant is a partner brand the service runs for. The data of every tenant is
+// kept apart from the others; prices and amounts of a tenant are in its
+// DefaultCurrency, an ISO 4217 code such as "RUB".
+type Tenant struct {
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	DefaultCurrency string `json:"default_currency"`
+}
@@ -0,0 +1,17 @@
+package model
+
+import "fmt"
+
+// ValidationError is input rejected by a service. Handlers answer it with 400
+// and its message, unlike other service errors, which are internal.
+type ValidationError struct {
+	Message string
+}
+
+func NewValidationError(format string, args ...any) *ValidationError {
+	return &ValidationError{Message: fmt.Sprintf(format, args...)}
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
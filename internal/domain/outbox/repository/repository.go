@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/infra/database"
+	"github.com/golangtestcases/subscribe-service/internal/infra/tenant"
+	"github.com/google/uuid"
+)
+
+// PostgreSQLRepository scopes every query to the tenant of its context.
+type PostgreSQLRepository struct {
+	db *sql.DB
+}
+
+func NewPostgreSQLRepository(db *sql.DB) *PostgreSQLRepository {
+	return &PostgreSQLRepository{db: db}
+}
+
+// conn returns the transaction carried by ctx, if any, or the connection pool.
+func (r *PostgreSQLRepository) conn(ctx context.Context) database.Querier {
+	return database.Conn(ctx, r.db)
+}
+
+// ClaimPending picks up to limit unpublished events of the tenant whose next
+// attempt is due, oldest first, and pushes their next attempt lease into the future so that
+// other instances skip them while they are being published.
+func (r *PostgreSQLRepository) ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]model.OutboxEntry, error) {
+	now := time.Now()
+
+	query := `
+		UPDATE outbox SET next_attempt_at = $4
+		WHERE id IN (
+			SELECT id FROM outbox
+			WHERE tenant_id = $1 AND published_at IS NULL AND next_attempt_at <= $3
+			ORDER BY created_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, tenant_id, event_type, payload, occurred_at, attempts, last_error, next_attempt_at, published_at
+	`
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, tenant.ID(ctx), limit, now, now.Add(lease))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []model.OutboxEntry
+	for rows.Next() {
+		var entry model.OutboxEntry
+		var payload []byte
+		err := rows.Scan(
+			&entry.ID, &entry.TenantID, &entry.Type, &payload, &entry.OccurredAt, &entry.Attempts,
+			&entry.LastError, &entry.NextAttemptAt, &entry.PublishedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		entry.Payload = payload
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+func (r *PostgreSQLRepository) MarkPublished(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE outbox SET published_at = $3, attempts = attempts + 1, last_error = NULL WHERE tenant_id = $1 AND id = $2`
+	_, err := r.conn(ctx).ExecContext(ctx, query, tenant.ID(ctx), id, time.Now())
+	return err
+}
+
+func (r *PostgreSQLRepository) MarkFailed(ctx context.Context, id uuid.UUID, lastError string, nextAttemptAt time.Time) error {
+	query := `UPDATE outbox SET attempts = attempts + 1, last_error = $3, next_attempt_at = $4 WHERE tenant_id = $1 AND id = $2`
+	_, err := r.conn(ctx).ExecContext(ctx, query, tenant.ID(ctx), id, lastError, nextAttemptAt)
+	return err
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+const (
+	// claimBatchSize is how many events are published per DispatchPending call.
+	// The claim lease grows with it, and so does the time a crashed instance
+	// holds events back.
+	claimBatchSize = 20
+	// claimLeaseMargin is added to the time publishing a batch may take, for
+	// the database writes between the events.
+	claimLeaseMargin = time.Minute
+	// maxRetryDelay caps the backoff between attempts to publish an event.
+	maxRetryDelay = 10 * time.Minute
+)
+
+type OutboxRepository interface {
+	ClaimPending(context.Context, int, time.Duration) ([]model.OutboxEntry, error)
+	MarkPublished(context.Context, uuid.UUID) error
+	MarkFailed(context.Context, uuid.UUID, string, time.Time) error
+}
+
+// Sink receives the events taken from the outbox. An event is retried until
+// every sink accepts it, so a sink may see the same event more than once and
+// has to tolerate that, e.g. by deduplicating on the event ID.
+type Sink interface {
+	Name() string
+	Publish(ctx context.Context, event model.Event) error
+}
+
+type OutboxService struct {
+	outboxRepository OutboxRepository
+	sinks            []Sink
+	sinkTimeout      time.Duration
+	logger           *slog.Logger
+}
+
+// NewOutboxService returns a service that gives a sink sinkTimeout to accept
+// an event before counting it as rejected.
+func NewOutboxService(outboxRepository OutboxRepository, sinks []Sink, sinkTimeout time.Duration, logger *slog.Logger) *OutboxService {
+	return &OutboxService{
+		outboxRepository: outboxRepository,
+		sinks:            sinks,
+		sinkTimeout:      sinkTimeout,
+		logger:           logger,
+	}
+}
+
+// DispatchPending publishes the due events to every sink. An event rejected by
+// any sink stays in the outbox and is retried with exponential backoff. It
+// returns how many events were dispatched.
+//
+// The events are claimed for as long as publishing all of them one after
+// another may take, so that other instances do not claim and publish them
+// again while they are still waiting for their turn.
+func (s *OutboxService) DispatchPending(ctx context.Context) (int, error) {
+	lease := time.Duration(claimBatchSize*len(s.sinks))*s.sinkTimeout + claimLeaseMargin
+	entries, err := s.outboxRepository.ClaimPending(ctx, claimBatchSize, lease)
+	if err != nil {
+		return 0, fmt.Errorf("outboxRepository.ClaimPending: %w", err)
+	}
+
+	for _, entry := range entries {
+		if err := s.publish(ctx, entry.Event); err != nil {
+			s.logger.Warn("failed to publish event",
+				"event_id", entry.ID, "type", entry.Type, "attempts", entry.Attempts+1, "error", err,
+			)
+
+			next := time.Now().Add(retryDelay(entry.Attempts + 1))
+			if err := s.outboxRepository.MarkFailed(ctx, entry.ID, err.Error(), next); err != nil {
+				return 0, fmt.Errorf("outboxRepository.MarkFailed: %w", err)
+			}
+			continue
+		}
+
+		if err := s.outboxRepository.MarkPublished(ctx, entry.ID); err != nil {
+			return 0, fmt.Errorf("outboxRepository.MarkPublished: %w", err)
+		}
+	}
+
+	return len(entries), nil
+}
+
+func (s *OutboxService) publish(ctx context.Context, event model.Event) error {
+	var errs []error
+	for _, sink := range s.sinks {
+		sinkCtx, cancel := context.WithTimeout(ctx, s.sinkTimeout)
+		err := sink.Publish(sinkCtx, event)
+		cancel()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sink.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// retryDelay returns 2^attempts seconds, capped at maxRetryDelay.
+func retryDelay(attempts int) time.Duration {
+	if attempts >= 10 {
+		return maxRetryDelay
+	}
+	return min(time.Duration(1<<attempts)*time.Second, maxRetryDelay)
+}
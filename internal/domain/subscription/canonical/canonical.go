@@ -0,0 +1,80 @@
+package canonical
+
+import (
+	"strings"
+	"unicode"
+)
+
+// tierWords are plan names that do not change which service is being paid for,
+// so "Spotify Premium" and "spotify family" both map to "spotify".
+var tierWords = map[string]bool{
+	"basic":        true,
+	"standard":     true,
+	"premium":      true,
+	"plus":         true,
+	"pro":          true,
+	"family":       true,
+	"individual":   true,
+	"duo":          true,
+	"student":      true,
+	"monthly":      true,
+	"annual":       true,
+	"yearly":       true,
+	"plan":         true,
+	"subscription": true,
+}
+
+// aliases maps alternative spellings to a single service key.
+var aliases = map[string]string{
+	"яндекс":      "yandex",
+	"кинопоиск":   "kinopoisk",
+	"иви":         "ivi",
+	"окко":        "okko",
+	"yt":          "youtube",
+	"netflixcom":  "netflix",
+	"appletv":     "apple tv",
+	"applemusic":  "apple music",
+	"amazonprime": "amazon prime",
+	"primevideo":  "amazon prime",
+}
+
+// Name returns the canonical key of a service name: lower-cased, without
+// punctuation and plan tiers, with known aliases resolved. Two subscriptions
+// with the same key are considered to be the same service.
+func Name(serviceName string) string {
+	return normalize(serviceName, false)
+}
+
+// Product is like Name but keeps the plan tier, so "Spotify Family" and
+// "Spotify Individual" are different products with different prices, while
+// "spotify family" and "Spotify-Family" are the same one.
+func Product(serviceName string) string {
+	return normalize(serviceName, true)
+}
+
+func normalize(serviceName string, keepTiers bool) string {
+	fields := strings.FieldsFunc(strings.ToLower(serviceName), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	words := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if tierWords[field] && !keepTiers {
+			continue
+		}
+		if alias, ok := aliases[field]; ok {
+			field = alias
+		}
+		words = append(words, field)
+	}
+
+	if len(words) == 0 {
+		return strings.Join(fields, " ")
+	}
+
+	name := strings.Join(words, " ")
+	if alias, ok := aliases[strings.ReplaceAll(name, " ", "")]; ok {
+		return alias
+	}
+	return name
+}
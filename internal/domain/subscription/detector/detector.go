@@ -0,0 +1,254 @@
+package detector
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+)
+
+// knownMerchants maps fragments of statement descriptions to service names.
+// The first matching fragment wins, so more specific ones come first.
+var knownMerchants = []struct {
+	fragment    string
+	serviceName string
+}{
+	{"youtube", "YouTube Premium"},
+	{"netflix", "Netflix"},
+	{"spotify", "Spotify"},
+	{"yandex plus", "Yandex Plus"},
+	{"yandex plyus", "Yandex Plus"},
+	{"яндекс плюс", "Yandex Plus"},
+	{"kinopoisk", "Kinopoisk"},
+	{"кинопоиск", "Kinopoisk"},
+	{"apple com bill", "Apple"},
+	{"itunes", "Apple"},
+	{"google play", "Google Play"},
+	{"google one", "Google One"},
+	{"amazon prime", "Amazon Prime"},
+	{"prime video", "Amazon Prime"},
+	{"disney", "Disney+"},
+	{"okko", "Okko"},
+	{"ivi", "ivi"},
+	{"start ru", "START"},
+	{"wink", "Wink"},
+	{"vk music", "VK Music"},
+	{"boom", "VK Music"},
+	{"telegram premium", "Telegram Premium"},
+	{"chatgpt", "ChatGPT Plus"},
+	{"openai", "ChatGPT Plus"},
+	{"dropbox", "Dropbox"},
+	{"adobe", "Adobe Creative Cloud"},
+	{"microsoft", "Microsoft 365"},
+	{"github", "GitHub"},
+}
+
+type cadenceRule struct {
+	cadence        model.BillingCadence
+	minDays        float64
+	maxDays        float64
+	minOccurrences int
+	months         int
+}
+
+var cadenceRules = []cadenceRule{
+	{cadence: model.BillingCadenceMonthly, minDays: 25, maxDays: 35, minOccurrences: 3, months: 1},
+	{cadence: model.BillingCadenceYearly, minDays: 350, maxDays: 380, minOccurrences: 2, months: 12},
+}
+
+// amountTolerance is how far a charge may be from the typical charge of the
+// merchant and still count as the same subscription, e.g. after a small price
+// change or currency conversion.
+const amountTolerance = 0.2
+
+// Detect finds recurring charges in the transactions: the same merchant
+// charging a similar amount at a monthly or yearly cadence. The statement is
+// considered to end at the last transaction; a subscription whose last charge
+// is more than one period and a grace week before that gets an end date.
+func Detect(transactions []model.Transaction) []model.SuggestedSubscription {
+	hasDebits := false
+	for _, transaction := range transactions {
+		if transaction.Amount < 0 {
+			hasDebits = true
+			break
+		}
+	}
+
+	charges := make([]model.Transaction, 0, len(transactions))
+	var statementEnd time.Time
+	for _, transaction := range transactions {
+		if transaction.Date.After(statementEnd) {
+			statementEnd = transaction.Date
+		}
+		// Some banks export charges as positive amounts; only when there are
+		// no negative amounts at all every transaction is treated as a charge.
+		if transaction.Amount < 0 || (!hasDebits && transaction.Amount > 0) {
+			transaction.Amount = math.Abs(transaction.Amount)
+			charges = append(charges, transaction)
+		}
+	}
+
+	byMerchant := make(map[string][]model.Transaction)
+	var merchants []string
+	for _, charge := range charges {
+		merchant := merchantKey(charge.Description)
+		if merchant == "" {
+			continue
+		}
+		if _, ok := byMerchant[merchant]; !ok {
+			merchants = append(merchants, merchant)
+		}
+		byMerchant[merchant] = append(byMerchant[merchant], charge)
+	}
+
+	var suggestions []model.SuggestedSubscription
+	for _, merchant := range merchants {
+		suggestion, ok := detectRecurring(merchant, byMerchant[merchant], statementEnd)
+		if ok {
+			suggestions = append(suggestions, suggestion)
+		}
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Confidence > suggestions[j].Confidence
+	})
+
+	return suggestions
+}
+
+func detectRecurring(merchant string, charges []model.Transaction, statementEnd time.Time) (model.SuggestedSubscription, bool) {
+	if len(charges) < 2 {
+		return model.SuggestedSubscription{}, false
+	}
+
+	sort.Slice(charges, func(i, j int) bool {
+		return charges[i].Date.Before(charges[j].Date)
+	})
+
+	amounts := make([]float64, 0, len(charges))
+	for _, charge := range charges {
+		amounts = append(amounts, charge.Amount)
+	}
+	typicalAmount := median(amounts)
+
+	// Charges of a clearly different amount are probably regular purchases
+	// from the same merchant rather than the subscription.
+	var recurring []model.Transaction
+	for _, charge := range charges {
+		if math.Abs(charge.Amount-typicalAmount) <= typicalAmount*amountTolerance {
+			recurring = append(recurring, charge)
+		}
+	}
+	if len(recurring) < 2 {
+		return model.SuggestedSubscription{}, false
+	}
+
+	intervals := make([]float64, 0, len(recurring)-1)
+	for i := 1; i < len(recurring); i++ {
+		intervals = append(intervals, recurring[i].Date.Sub(recurring[i-1].Date).Hours()/24)
+	}
+	typicalInterval := median(intervals)
+
+	for _, rule := range cadenceRules {
+		if typicalInterval < rule.minDays || typicalInterval > rule.maxDays || len(recurring) < rule.minOccurrences {
+			continue
+		}
+
+		regular := 0
+		for _, interval := range intervals {
+			if interval >= rule.minDays && interval <= rule.maxDays {
+				regular++
+			}
+		}
+		confidence := float64(regular) / float64(len(intervals))
+		if confidence < 0.5 {
+			return model.SuggestedSubscription{}, false
+		}
+
+		first := recurring[0]
+		last := recurring[len(recurring)-1]
+		chargeAmount := int(math.Round(typicalAmount))
+
+		subscription := model.Subscription{
+			ServiceName: serviceName(merchant),
+			Price:       max(1, int(math.Round(typicalAmount/float64(rule.months)))),
+			StartDate:   monthStart(first.Date),
+		}
+		if statementEnd.Sub(last.Date).Hours()/24 > rule.maxDays+7 {
+			endDate := monthStart(last.Date).AddDate(0, rule.months, 0)
+			subscription.EndDate = &endDate
+		}
+
+		return model.SuggestedSubscription{
+			Subscription:   subscription,
+			Merchant:       merchant,
+			Cadence:        rule.cadence,
+			ChargeAmount:   chargeAmount,
+			Occurrences:    len(recurring),
+			LastChargeDate: last.Date,
+			Confidence:     math.Round(confidence*100) / 100,
+		}, true
+	}
+
+	return model.SuggestedSubscription{}, false
+}
+
+// merchantKey reduces a statement description to the merchant name: lower
+// case, without punctuation and without tokens containing digits such as card
+// numbers, dates and order references.
+func merchantKey(description string) string {
+	fields := strings.FieldsFunc(strings.ToLower(description), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	words := make([]string, 0, 3)
+	for _, field := range fields {
+		if strings.IndexFunc(field, unicode.IsDigit) >= 0 || len([]rune(field)) < 2 {
+			continue
+		}
+		words = append(words, field)
+		if len(words) == 3 {
+			break
+		}
+	}
+
+	return strings.Join(words, " ")
+}
+
+// serviceName maps a merchant key to a known service, falling back to the
+// merchant itself with capitalised words.
+func serviceName(merchant string) string {
+	padded := " " + merchant + " "
+	for _, known := range knownMerchants {
+		// Short fragments such as "ivi" must match a whole word.
+		if strings.Contains(padded, " "+known.fragment+" ") || (len(known.fragment) > 4 && strings.Contains(merchant, known.fragment)) {
+			return known.serviceName
+		}
+	}
+
+	words := strings.Fields(merchant)
+	for i, word := range words {
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		words[i] = string(runes)
+	}
+	return strings.Join(words, " ")
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
+
+func monthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
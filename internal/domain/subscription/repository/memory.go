@@ -0,0 +1,405 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/infra/tenant"
+	"github.com/google/uuid"
+)
+
+// InMemoryRepository keeps subscriptions in memory with the same semantics as
+// PostgreSQLRepository: tenant scope, ordering, cost filters, sql.ErrNoRows for
+// missing rows and transactions. It is safe for concurrent use; transactions are serialised
+// and a failed one restores the state it started from. The repository is its
+// own transactor, so it is passed to the service twice.
+type InMemoryRepository struct {
+	mu      sync.Mutex
+	state   memoryState
+	seq     int64
+	lockKey *int
+}
+
+type memoryState struct {
+	subscriptions map[uuid.UUID]memorySubscription
+	members       map[uuid.UUID][]model.SubscriptionMember
+	events        []model.Event
+	eventIDs      map[uuid.UUID]bool
+}
+
+// memorySubscription remembers its tenant and the insertion order, which
+// breaks ties between subscriptions created at the same moment.
+type memorySubscription struct {
+	model.Subscription
+	tenantID string
+	seq      int64
+}
+
+func NewInMemoryRepository() *InMemoryRepository {
+	return &InMemoryRepository{
+		state: memoryState{
+			subscriptions: make(map[uuid.UUID]memorySubscription),
+			members:       make(map[uuid.UUID][]model.SubscriptionMember),
+			eventIDs:      make(map[uuid.UUID]bool),
+		},
+		lockKey: new(int),
+	}
+}
+
+// lock acquires the repository unless ctx belongs to one of its transactions,
+// which already holds it.
+func (r *InMemoryRepository) lock(ctx context.Context) func() {
+	if ctx.Value(r.lockKey) != nil {
+		return func() {}
+	}
+	r.mu.Lock()
+	return r.mu.Unlock
+}
+
+func (r *InMemoryRepository) CreateSubscription(ctx context.Context, subscription model.Subscription) (model.Subscription, error) {
+	defer r.lock(ctx)()
+
+	subscription.ID = uuid.New()
+	subscription.CreatedAt = time.Now()
+	subscription.UpdatedAt = time.Now()
+
+	r.seq++
+	r.state.subscriptions[subscription.ID] = memorySubscription{Subscription: subscription, tenantID: tenant.ID(ctx), seq: r.seq}
+
+	return subscription, nil
+}
+
+func (r *InMemoryRepository) GetSubscriptionByID(ctx context.Context, id uuid.UUID) (model.Subscription, error) {
+	defer r.lock(ctx)()
+
+	stored, ok := r.get(ctx, id)
+	if !ok {
+		return model.Subscription{}, sql.ErrNoRows
+	}
+	return stored.Subscription, nil
+}
+
+func (r *InMemoryRepository) UpdateSubscription(ctx context.Context, subscription model.Subscription) error {
+	defer r.lock(ctx)()
+
+	stored, ok := r.get(ctx, subscription.ID)
+	if !ok {
+		return sql.ErrNoRows
+	}
+
+	subscription.CreatedAt = stored.CreatedAt
+	subscription.UpdatedAt = time.Now()
+	stored.Subscription = subscription
+	r.state.subscriptions[subscription.ID] = stored
+
+	return nil
+}
+
+func (r *InMemoryRepository) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	defer r.lock(ctx)()
+
+	if _, ok := r.get(ctx, id); !ok {
+		return sql.ErrNoRows
+	}
+
+	delete(r.state.subscriptions, id)
+	delete(r.state.members, id)
+
+	return nil
+}
+
+func (r *InMemoryRepository) ListSubscriptions(ctx context.Context, userID *uuid.UUID, limit, offset int) ([]model.Subscription, error) {
+	defer r.lock(ctx)()
+
+	subscriptions := r.newestFirst(ctx)
+	if userID != nil {
+		subscriptions = slices.DeleteFunc(subscriptions, func(subscription model.Subscription) bool {
+			return subscription.UserID != *userID
+		})
+	}
+
+	return page(subscriptions, limit, offset), nil
+}
+
+// StreamSubscriptions calls fn for each subscription in the same order as
+// ListSubscriptions. The page is copied first, so fn may use the repository.
+// A nil userID means subscriptions of all users and a limit of zero means no
+// limit.
+func (r *InMemoryRepository) StreamSubscriptions(ctx context.Context, userID *uuid.UUID, limit, offset int, fn func(model.Subscription) error) error {
+	unlock := r.lock(ctx)
+	subscriptions := r.newestFirst(ctx)
+	if userID != nil {
+		subscriptions = slices.DeleteFunc(subscriptions, func(subscription model.Subscription) bool {
+			return subscription.UserID != *userID
+		})
+	}
+	if limit <= 0 {
+		limit = len(subscriptions)
+	}
+	subscriptions = page(subscriptions, limit, offset)
+	unlock()
+
+	for _, subscription := range subscriptions {
+		if err := fn(subscription); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ListActiveSubscriptions returns the subscriptions active at the given moment.
+// An empty userIDs slice means subscriptions of all users.
+func (r *InMemoryRepository) ListActiveSubscriptions(ctx context.Context, userIDs []uuid.UUID, at time.Time) ([]model.Subscription, error) {
+	defer r.lock(ctx)()
+
+	users := make(map[uuid.UUID]bool, len(userIDs))
+	for _, userID := range userIDs {
+		users[userID] = true
+	}
+
+	var active []memorySubscription
+	for _, stored := range r.state.subscriptions {
+		if stored.tenantID != tenant.ID(ctx) {
+			continue
+		}
+		if stored.StartDate.After(at) || (stored.EndDate != nil && stored.EndDate.Before(at)) {
+			continue
+		}
+		if len(users) > 0 && !users[stored.UserID] {
+			continue
+		}
+		active = append(active, stored)
+	}
+
+	sort.Slice(active, func(i, j int) bool {
+		if c := bytes.Compare(active[i].UserID[:], active[j].UserID[:]); c != 0 {
+			return c < 0
+		}
+		return olderFirst(active[i], active[j])
+	})
+
+	return unwrap(active), nil
+}
+
+// GetTotalCost sums subscription prices matching the filter, counting shared
+// subscriptions the same way as PostgreSQLRepository.GetTotalCost.
+func (r *InMemoryRepository) GetTotalCost(ctx context.Context, filter model.CostFilter) (int, error) {
+	defer r.lock(ctx)()
+
+	total := 0.0
+	for _, stored := range r.state.subscriptions {
+		if stored.tenantID != tenant.ID(ctx) {
+			continue
+		}
+		if filter.ServiceName != nil && !strings.Contains(strings.ToLower(stored.ServiceName), strings.ToLower(*filter.ServiceName)) {
+			continue
+		}
+		if filter.StartDate != nil && stored.StartDate.Before(*filter.StartDate) {
+			continue
+		}
+		if filter.EndDate != nil && stored.EndDate != nil && stored.EndDate.After(*filter.EndDate) {
+			continue
+		}
+
+		if filter.UserID == nil {
+			total += float64(stored.Price)
+			continue
+		}
+
+		members := r.state.members[stored.ID]
+		if len(members) == 0 {
+			if stored.UserID == *filter.UserID {
+				total += float64(stored.Price)
+			}
+			continue
+		}
+		for _, member := range members {
+			if member.UserID == *filter.UserID {
+				total += float64(stored.Price) * member.Share
+			}
+		}
+	}
+
+	return int(math.Round(total)), nil
+}
+
+func (r *InMemoryRepository) ListSubscriptionMembers(ctx context.Context, subscriptionID uuid.UUID) ([]model.SubscriptionMember, error) {
+	defer r.lock(ctx)()
+
+	if _, ok := r.get(ctx, subscriptionID); !ok {
+		return nil, nil
+	}
+
+	members := append([]model.SubscriptionMember(nil), r.state.members[subscriptionID]...)
+	sort.Slice(members, func(i, j int) bool {
+		return bytes.Compare(members[i].UserID[:], members[j].UserID[:]) < 0
+	})
+
+	return members, nil
+}
+
+// SetSubscriptionMembers replaces all members of the subscription. Shares are
+// kept with four decimal places, like the NUMERIC(5, 4) column. The members
+// of a subscription of another tenant cannot be changed.
+func (r *InMemoryRepository) SetSubscriptionMembers(ctx context.Context, subscriptionID uuid.UUID, members []model.SubscriptionMember) error {
+	defer r.lock(ctx)()
+
+	if stored, ok := r.state.subscriptions[subscriptionID]; ok && stored.tenantID != tenant.ID(ctx) {
+		return sql.ErrNoRows
+	}
+
+	if len(members) == 0 {
+		delete(r.state.members, subscriptionID)
+		return nil
+	}
+
+	stored := make([]model.SubscriptionMember, 0, len(members))
+	for _, member := range members {
+		member.SubscriptionID = subscriptionID
+		member.Share = math.Round(member.Share*10000) / 10000
+		stored = append(stored, member)
+	}
+	r.state.members[subscriptionID] = stored
+
+	return nil
+}
+
+// AddEvent keeps the event in memory, together with the tenant of ctx; see
+// Events. An event whose ID was already added is ignored.
+func (r *InMemoryRepository) AddEvent(ctx context.Context, event model.Event) error {
+	defer r.lock(ctx)()
+
+	if r.state.eventIDs[event.ID] {
+		return nil
+	}
+	event.TenantID = tenant.ID(ctx)
+	r.state.eventIDs[event.ID] = true
+	r.state.events = append(r.state.events, event)
+
+	return nil
+}
+
+// Events returns the events added so far in every tenant, oldest first.
+func (r *InMemoryRepository) Events() []model.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]model.Event(nil), r.state.events...)
+}
+
+// WithinTx runs fn with the repository locked for other callers. If fn returns
+// an error or panics, every change it made is undone. If ctx already carries a
+// transaction of this repository, fn joins it.
+func (r *InMemoryRepository) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if ctx.Value(r.lockKey) != nil {
+		return fn(ctx)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.runRestoringOnFailure(context.WithValue(ctx, r.lockKey, true), fn)
+}
+
+// WithinSavepoint undoes only the changes made by fn when it fails, leaving
+// the surrounding transaction usable. Without a transaction fn simply runs on
+// its own.
+func (r *InMemoryRepository) WithinSavepoint(ctx context.Context, fn func(ctx context.Context) error) error {
+	if ctx.Value(r.lockKey) == nil {
+		return fn(ctx)
+	}
+
+	return r.runRestoringOnFailure(ctx, fn)
+}
+
+func (r *InMemoryRepository) runRestoringOnFailure(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	snapshot := r.state.clone()
+	defer func() {
+		if p := recover(); p != nil {
+			r.state = snapshot
+			panic(p)
+		}
+		if err != nil {
+			r.state = snapshot
+		}
+	}()
+
+	return fn(ctx)
+}
+
+func (s memoryState) clone() memoryState {
+	clone := memoryState{
+		subscriptions: make(map[uuid.UUID]memorySubscription, len(s.subscriptions)),
+		members:       make(map[uuid.UUID][]model.SubscriptionMember, len(s.members)),
+		events:        append([]model.Event(nil), s.events...),
+		eventIDs:      make(map[uuid.UUID]bool, len(s.eventIDs)),
+	}
+	for id, subscription := range s.subscriptions {
+		clone.subscriptions[id] = subscription
+	}
+	for id, members := range s.members {
+		clone.members[id] = append([]model.SubscriptionMember(nil), members...)
+	}
+	for id := range s.eventIDs {
+		clone.eventIDs[id] = true
+	}
+	return clone
+}
+
+// get returns the subscription with the ID if it belongs to the tenant of ctx.
+func (r *InMemoryRepository) get(ctx context.Context, id uuid.UUID) (memorySubscription, bool) {
+	stored, ok := r.state.subscriptions[id]
+	if !ok || stored.tenantID != tenant.ID(ctx) {
+		return memorySubscription{}, false
+	}
+	return stored, true
+}
+
+// newestFirst returns the subscriptions of the tenant of ctx.
+func (r *InMemoryRepository) newestFirst(ctx context.Context) []model.Subscription {
+	all := make([]memorySubscription, 0, len(r.state.subscriptions))
+	for _, stored := range r.state.subscriptions {
+		if stored.tenantID == tenant.ID(ctx) {
+			all = append(all, stored)
+		}
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return olderFirst(all[j], all[i])
+	})
+	return unwrap(all)
+}
+
+func olderFirst(a, b memorySubscription) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.Before(b.CreatedAt)
+	}
+	return a.seq < b.seq
+}
+
+func unwrap(stored []memorySubscription) []model.Subscription {
+	var subscriptions []model.Subscription
+	for _, s := range stored {
+		subscriptions = append(subscriptions, s.Subscription)
+	}
+	return subscriptions
+}
+
+func page(subscriptions []model.Subscription, limit, offset int) []model.Subscription {
+	if offset >= len(subscriptions) {
+		return nil
+	}
+	subscriptions = subscriptions[offset:]
+	if limit < len(subscriptions) {
+		subscriptions = subscriptions[:limit]
+	}
+	return subscriptions
+}
@@ -0,0 +1,18 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/subscription/repository"
+	"github.com/golangtestcases/subscribe-service/internal/domain/subscription/repository/repositorytest"
+)
+
+func TestInMemoryRepository(t *testing.T) {
+	err := repositorytest.TestRepository(context.Background(), func() (repositorytest.Repository, error) {
+		return repository.NewInMemoryRepository(), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,71 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/lib/pq"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/subscription/repository"
+	"github.com/golangtestcases/subscribe-service/internal/domain/subscription/repository/repositorytest"
+	"github.com/golangtestcases/subscribe-service/internal/infra/database"
+)
+
+// TestPostgreSQLRepository runs against the database in TEST_POSTGRES_DSN,
+// whose subscription data it deletes. It is skipped without one. Connect as a
+// role that is neither a superuser nor has BYPASSRLS, so that the row-level
+// security policies are checked too.
+func TestPostgreSQLRepository(t *testing.T) {
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN is not set")
+	}
+
+	admin, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer admin.Close()
+	if err := migratePostgreSQL(admin); err != nil {
+		t.Fatal(err)
+	}
+
+	connector, err := pq.NewConnector(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := sql.OpenDB(database.NewTenantConnector(connector))
+	defer db.Close()
+
+	ctx := context.Background()
+	err = repositorytest.TestRepository(ctx, func() (repositorytest.Repository, error) {
+		if _, err := admin.ExecContext(ctx, "TRUNCATE subscriptions, subscription_members, outbox CASCADE"); err != nil {
+			return nil, err
+		}
+		r := repository.NewPostgreSQLRepository(db, database.NewReadRouter(db, nil, 0))
+		return transacted{r, database.NewTransactor(db)}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func migratePostgreSQL(db *sql.DB) error {
+	driver, err := postgres.WithInstance(db, &postgres.Config{})
+	if err != nil {
+		return err
+	}
+	m, err := migrate.NewWithDatabaseInstance("file://../../../../migrations", "postgres", driver)
+	if err != nil {
+		return err
+	}
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+	return nil
+}
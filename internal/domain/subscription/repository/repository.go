@@ -7,16 +7,32 @@ import (
 	"time"
 
 	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/infra/database"
+	"github.com/golangtestcases/subscribe-service/internal/infra/tenant"
 	"github.com/google/uuid"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
+// PostgreSQLRepository writes to the primary database. Reports, which may be
+// slightly stale, are read through reads, which prefers a healthy replica.
+// Every query is scoped to the tenant of its context.
 type PostgreSQLRepository struct {
-	db *sql.DB
+	db    *sql.DB
+	reads *database.ReadRouter
 }
 
-func NewPostgreSQLRepository(db *sql.DB) *PostgreSQLRepository {
-	return &PostgreSQLRepository{db: db}
+func NewPostgreSQLRepository(db *sql.DB, reads *database.ReadRouter) *PostgreSQLRepository {
+	return &PostgreSQLRepository{db: db, reads: reads}
+}
+
+// conn returns the transaction carried by ctx, if any, or the connection pool.
+func (r *PostgreSQLRepository) conn(ctx context.Context) database.Querier {
+	return database.Conn(ctx, r.db)
+}
+
+// readConn is like conn, but outside a transaction it may return the replica.
+func (r *PostgreSQLRepository) readConn(ctx context.Context) database.Querier {
+	return r.reads.Conn(ctx)
 }
 
 func (r *PostgreSQLRepository) CreateSubscription(ctx context.Context, subscription model.Subscription) (model.Subscription, error) {
@@ -25,12 +41,12 @@ func (r *PostgreSQLRepository) CreateSubscription(ctx context.Context, subscript
 	subscription.UpdatedAt = time.Now()
 
 	query := `
-		INSERT INTO subscriptions (id, service_name, price, user_id, start_date, end_date, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO subscriptions (id, tenant_id, service_name, price, user_id, start_date, end_date, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
-		subscription.ID, subscription.ServiceName, subscription.Price,
+	_, err := r.conn(ctx).ExecContext(ctx, query,
+		subscription.ID, tenant.ID(ctx), subscription.ServiceName, subscription.Price,
 		subscription.UserID, subscription.StartDate, subscription.EndDate,
 		subscription.CreatedAt, subscription.UpdatedAt,
 	)
@@ -42,10 +58,10 @@ func (r *PostgreSQLRepository) GetSubscriptionByID(ctx context.Context, id uuid.
 	var subscription model.Subscription
 	query := `
 		SELECT id, service_name, price, user_id, start_date, end_date, created_at, updated_at
-		FROM subscriptions WHERE id = $1
+		FROM subscriptions WHERE tenant_id = $1 AND id = $2
 	`
 
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err := r.conn(ctx).QueryRowContext(ctx, query, tenant.ID(ctx), id).Scan(
 		&subscription.ID, &subscription.ServiceName, &subscription.Price,
 		&subscription.UserID, &subscription.StartDate, &subscription.EndDate,
 		&subscription.CreatedAt, &subscription.UpdatedAt,
@@ -59,32 +75,121 @@ func (r *PostgreSQLRepository) UpdateSubscription(ctx context.Context, subscript
 
 	query := `
 		UPDATE subscriptions 
-		SET service_name = $2, price = $3, user_id = $4, start_date = $5, end_date = $6, updated_at = $7
-		WHERE id = $1
+		SET service_name = $3, price = $4, user_id = $5, start_date = $6, end_date = $7, updated_at = $8
+		WHERE tenant_id = $1 AND id = $2
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
-		subscription.ID, subscription.ServiceName, subscription.Price,
+	result, err := r.conn(ctx).ExecContext(ctx, query,
+		tenant.ID(ctx), subscription.ID, subscription.ServiceName, subscription.Price,
 		subscription.UserID, subscription.StartDate, subscription.EndDate,
 		subscription.UpdatedAt,
 	)
+	if err != nil {
+		return err
+	}
 
-	return err
+	return checkRowsAffected(result)
 }
 
 func (r *PostgreSQLRepository) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
-	query := `DELETE FROM subscriptions WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, id)
-	return err
+	query := `DELETE FROM subscriptions WHERE tenant_id = $1 AND id = $2`
+	result, err := r.conn(ctx).ExecContext(ctx, query, tenant.ID(ctx), id)
+	if err != nil {
+		return err
+	}
+
+	return checkRowsAffected(result)
+}
+
+// ListSubscriptions returns a page of the subscriptions paid by the user, or
+// of all subscriptions of the tenant if userID is nil, newest first.
+func (r *PostgreSQLRepository) ListSubscriptions(ctx context.Context, userID *uuid.UUID, limit, offset int) ([]model.Subscription, error) {
+	query := `
+		SELECT id, service_name, price, user_id, start_date, end_date, created_at, updated_at
+		FROM subscriptions WHERE tenant_id = $1 AND ($2::UUID IS NULL OR user_id = $2)
+		ORDER BY created_at DESC LIMIT $3 OFFSET $4
+	`
+
+	rows, err := r.readConn(ctx).QueryContext(ctx, query, tenant.ID(ctx), userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subscriptions []model.Subscription
+	for rows.Next() {
+		var subscription model.Subscription
+		err := rows.Scan(
+			&subscription.ID, &subscription.ServiceName, &subscription.Price,
+			&subscription.UserID, &subscription.StartDate, &subscription.EndDate,
+			&subscription.CreatedAt, &subscription.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	return subscriptions, rows.Err()
+}
+
+// StreamSubscriptions calls fn for each subscription in the same order as
+// ListSubscriptions, reading rows from the database as it goes instead of
+// collecting them first. A nil userID means subscriptions of all users and a
+// limit of zero means no limit.
+func (r *PostgreSQLRepository) StreamSubscriptions(ctx context.Context, userID *uuid.UUID, limit, offset int, fn func(model.Subscription) error) error {
+	query := `
+		SELECT id, service_name, price, user_id, start_date, end_date, created_at, updated_at
+		FROM subscriptions WHERE tenant_id = $1 AND ($2::UUID IS NULL OR user_id = $2)
+		ORDER BY created_at DESC LIMIT $3 OFFSET $4
+	`
+
+	var limitArg *int
+	if limit > 0 {
+		limitArg = &limit
+	}
+
+	rows, err := r.readConn(ctx).QueryContext(ctx, query, tenant.ID(ctx), userID, limitArg, offset)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var subscription model.Subscription
+		err := rows.Scan(
+			&subscription.ID, &subscription.ServiceName, &subscription.Price,
+			&subscription.UserID, &subscription.StartDate, &subscription.EndDate,
+			&subscription.CreatedAt, &subscription.UpdatedAt,
+		)
+		if err != nil {
+			return err
+		}
+		if err := fn(subscription); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
 }
 
-func (r *PostgreSQLRepository) ListSubscriptions(ctx context.Context, limit, offset int) ([]model.Subscription, error) {
+// ListActiveSubscriptions returns the subscriptions active at the given moment.
+// An empty userIDs slice means subscriptions of all users.
+func (r *PostgreSQLRepository) ListActiveSubscriptions(ctx context.Context, userIDs []uuid.UUID, at time.Time) ([]model.Subscription, error) {
 	query := `
 		SELECT id, service_name, price, user_id, start_date, end_date, created_at, updated_at
-		FROM subscriptions ORDER BY created_at DESC LIMIT $1 OFFSET $2
+		FROM subscriptions
+		WHERE tenant_id = $1 AND start_date <= $2 AND (end_date IS NULL OR end_date >= $2)
+			AND (CARDINALITY($3::UUID[]) = 0 OR user_id = ANY($3::UUID[]))
+		ORDER BY user_id, created_at
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	ids := make([]string, 0, len(userIDs))
+	for _, userID := range userIDs {
+		ids = append(ids, userID.String())
+	}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, tenant.ID(ctx), at, pq.StringArray(ids))
 	if err != nil {
 		return nil, err
 	}
@@ -107,35 +212,116 @@ func (r *PostgreSQLRepository) ListSubscriptions(ctx context.Context, limit, off
 	return subscriptions, rows.Err()
 }
 
+// GetTotalCost sums subscription prices matching the filter. Without a user a
+// shared subscription is counted once at its full price. With a user only that
+// user's part is counted: their share of subscriptions they are a member of,
+// and the full price of subscriptions they pay for that have no members.
 func (r *PostgreSQLRepository) GetTotalCost(ctx context.Context, filter model.CostFilter) (int, error) {
-	query := `SELECT COALESCE(SUM(price), 0) FROM subscriptions WHERE 1=1`
-	args := []interface{}{}
-	argIndex := 1
+	query := `SELECT COALESCE(SUM(s.price), 0) FROM subscriptions s WHERE s.tenant_id = $1`
+	args := []interface{}{tenant.ID(ctx)}
+	argIndex := 2
 
 	if filter.UserID != nil {
-		query += fmt.Sprintf(" AND user_id = $%d", argIndex)
+		query = fmt.Sprintf(`
+			SELECT COALESCE(ROUND(SUM(s.price * COALESCE(m.share, 1))), 0)::INTEGER
+			FROM subscriptions s
+			LEFT JOIN subscription_members m ON m.subscription_id = s.id AND m.user_id = $%[1]d
+			WHERE s.tenant_id = $1 AND (m.user_id IS NOT NULL OR (s.user_id = $%[1]d AND NOT EXISTS (
+				SELECT 1 FROM subscription_members sm WHERE sm.subscription_id = s.id
+			)))`, argIndex)
 		args = append(args, *filter.UserID)
 		argIndex++
 	}
 
 	if filter.ServiceName != nil {
-		query += fmt.Sprintf(" AND service_name ILIKE $%d", argIndex)
+		query += fmt.Sprintf(" AND s.service_name ILIKE $%d", argIndex)
 		args = append(args, "%"+*filter.ServiceName+"%")
 		argIndex++
 	}
 
 	if filter.StartDate != nil {
-		query += fmt.Sprintf(" AND start_date >= $%d", argIndex)
+		query += fmt.Sprintf(" AND s.start_date >= $%d", argIndex)
 		args = append(args, *filter.StartDate)
 		argIndex++
 	}
 
 	if filter.EndDate != nil {
-		query += fmt.Sprintf(" AND (end_date IS NULL OR end_date <= $%d)", argIndex)
+		query += fmt.Sprintf(" AND (s.end_date IS NULL OR s.end_date <= $%d)", argIndex)
 		args = append(args, *filter.EndDate)
 	}
 
 	var totalCost int
-	err := r.db.QueryRowContext(ctx, query, args...).Scan(&totalCost)
+	err := r.readConn(ctx).QueryRowContext(ctx, query, args...).Scan(&totalCost)
 	return totalCost, err
 }
+
+func (r *PostgreSQLRepository) ListSubscriptionMembers(ctx context.Context, subscriptionID uuid.UUID) ([]model.SubscriptionMember, error) {
+	query := `
+		SELECT subscription_id, user_id, share
+		FROM subscription_members WHERE tenant_id = $1 AND subscription_id = $2 ORDER BY user_id
+	`
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, tenant.ID(ctx), subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var members []model.SubscriptionMember
+	for rows.Next() {
+		var member model.SubscriptionMember
+		if err := rows.Scan(&member.SubscriptionID, &member.UserID, &member.Share); err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+
+	return members, rows.Err()
+}
+
+// SetSubscriptionMembers replaces all members of the subscription. It should
+// run within a transaction so that a failure does not leave the members
+// half-replaced.
+func (r *PostgreSQLRepository) SetSubscriptionMembers(ctx context.Context, subscriptionID uuid.UUID, members []model.SubscriptionMember) error {
+	query := `DELETE FROM subscription_members WHERE tenant_id = $1 AND subscription_id = $2`
+	if _, err := r.conn(ctx).ExecContext(ctx, query, tenant.ID(ctx), subscriptionID); err != nil {
+		return err
+	}
+
+	for _, member := range members {
+		query := `INSERT INTO subscription_members (tenant_id, subscription_id, user_id, share) VALUES ($1, $2, $3, $4)`
+		if _, err := r.conn(ctx).ExecContext(ctx, query, tenant.ID(ctx), subscriptionID, member.UserID, member.Share); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddEvent writes the event to the outbox. Called with the context of a
+// transaction, the event is stored only if the change it describes is
+// committed. An event whose ID is already in the outbox is ignored.
+func (r *PostgreSQLRepository) AddEvent(ctx context.Context, event model.Event) error {
+	query := `
+		INSERT INTO outbox (id, tenant_id, event_type, payload, occurred_at, next_attempt_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+		ON CONFLICT (id) DO NOTHING
+	`
+
+	_, err := r.conn(ctx).ExecContext(ctx, query,
+		event.ID, tenant.ID(ctx), event.Type, []byte(event.Payload), event.OccurredAt, time.Now(),
+	)
+
+	return err
+}
+
+func checkRowsAffected(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,625 @@
+// Package repositorytest checks implementations of the subscription
+// repository against the behaviour the service relies on, in the manner of
+// testing/fstest. Every implementation must pass the same suite.
+package repositorytest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/domain/subscription/service"
+	"github.com/golangtestcases/subscribe-service/internal/infra/tenant"
+	"github.com/google/uuid"
+)
+
+// Repository is a subscription repository that is also its own transactor,
+// as InMemoryRepository is. For PostgreSQLRepository combine it with a
+// database.Transactor on the same database.
+type Repository interface {
+	service.SubscriptionRepository
+	service.Transactor
+	service.EventRecorder
+}
+
+type check struct {
+	name string
+	run  func(ctx context.Context, r Repository) error
+}
+
+var checks = []check{
+	{"create and get", checkCreateAndGet},
+	{"update", checkUpdate},
+	{"delete", checkDelete},
+	{"list order and paging", checkListOrder},
+	{"stream", checkStream},
+	{"active subscriptions", checkActive},
+	{"total cost", checkTotalCost},
+	{"members", checkMembers},
+	{"transactions", checkTransactions},
+	{"events", checkEvents},
+	{"tenant isolation", checkTenantIsolation},
+}
+
+// suiteTenant is the tenant the checks act for unless they switch tenants.
+var suiteTenant = model.Tenant{ID: "repositorytest", DefaultCurrency: "RUB"}
+
+// TestRepository runs every check against a fresh, empty repository returned
+// by newRepository and reports all failures together.
+func TestRepository(ctx context.Context, newRepository func() (Repository, error)) error {
+	ctx = tenant.WithTenant(ctx, suiteTenant)
+
+	var errs []error
+	for _, c := range checks {
+		r, err := newRepository()
+		if err != nil {
+			return fmt.Errorf("newRepository: %w", err)
+		}
+		if err := c.run(ctx, r); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", c.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func checkCreateAndGet(ctx context.Context, r Repository) error {
+	endDate := month(2025, 6)
+	created, err := r.CreateSubscription(ctx, model.Subscription{
+		ServiceName: "Netflix",
+		Price:       599,
+		UserID:      uuid.New(),
+		StartDate:   month(2025, 1),
+		EndDate:     &endDate,
+	})
+	if err != nil {
+		return err
+	}
+	if created.ID == uuid.Nil || created.CreatedAt.IsZero() {
+		return errors.New("created subscription has no ID or creation time")
+	}
+
+	got, err := r.GetSubscriptionByID(ctx, created.ID)
+	if err != nil {
+		return err
+	}
+	if err := sameSubscription(got, created); err != nil {
+		return err
+	}
+
+	if _, err := r.GetSubscriptionByID(ctx, uuid.New()); !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("get of a missing subscription returned %v, want sql.ErrNoRows", err)
+	}
+	return nil
+}
+
+func checkUpdate(ctx context.Context, r Repository) error {
+	created, err := r.CreateSubscription(ctx, subscription("Spotify", 299, uuid.New(), month(2025, 1)))
+	if err != nil {
+		return err
+	}
+
+	updated := created
+	updated.ServiceName = "Spotify Family"
+	updated.Price = 449
+	if err := r.UpdateSubscription(ctx, updated); err != nil {
+		return err
+	}
+
+	got, err := r.GetSubscriptionByID(ctx, created.ID)
+	if err != nil {
+		return err
+	}
+	if err := sameSubscription(got, updated); err != nil {
+		return err
+	}
+	if !got.CreatedAt.Round(time.Millisecond).Equal(created.CreatedAt.Round(time.Millisecond)) {
+		return errors.New("update changed created_at")
+	}
+
+	missing := updated
+	missing.ID = uuid.New()
+	if err := r.UpdateSubscription(ctx, missing); !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("update of a missing subscription returned %v, want sql.ErrNoRows", err)
+	}
+	return nil
+}
+
+func checkDelete(ctx context.Context, r Repository) error {
+	created, err := r.CreateSubscription(ctx, subscription("YouTube", 399, uuid.New(), month(2025, 1)))
+	if err != nil {
+		return err
+	}
+	member := model.SubscriptionMember{UserID: uuid.New(), Share: 1}
+	if err := r.SetSubscriptionMembers(ctx, created.ID, []model.SubscriptionMember{member}); err != nil {
+		return err
+	}
+
+	if err := r.DeleteSubscription(ctx, created.ID); err != nil {
+		return err
+	}
+	if _, err := r.GetSubscriptionByID(ctx, created.ID); !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("get after delete returned %v, want sql.ErrNoRows", err)
+	}
+	members, err := r.ListSubscriptionMembers(ctx, created.ID)
+	if err != nil {
+		return err
+	}
+	if len(members) != 0 {
+		return errors.New("members of a deleted subscription were kept")
+	}
+
+	if err := r.DeleteSubscription(ctx, created.ID); !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("second delete returned %v, want sql.ErrNoRows", err)
+	}
+	return nil
+}
+
+func checkListOrder(ctx context.Context, r Repository) error {
+	ids, err := createSequence(ctx, r, 5)
+	if err != nil {
+		return err
+	}
+
+	all, err := r.ListSubscriptions(ctx, nil, 10, 0)
+	if err != nil {
+		return err
+	}
+	if err := sameIDs(all, reversed(ids)); err != nil {
+		return fmt.Errorf("list is not newest first: %w", err)
+	}
+
+	paged, err := r.ListSubscriptions(ctx, nil, 2, 1)
+	if err != nil {
+		return err
+	}
+	if err := sameIDs(paged, reversed(ids)[1:3]); err != nil {
+		return fmt.Errorf("limit 2 offset 1: %w", err)
+	}
+
+	beyond, err := r.ListSubscriptions(ctx, nil, 10, 10)
+	if err != nil {
+		return err
+	}
+	if len(beyond) != 0 {
+		return errors.New("offset past the end returned subscriptions")
+	}
+
+	other, err := r.CreateSubscription(ctx, subscription("Other", 100, uuid.New(), month(2025, 1)))
+	if err != nil {
+		return err
+	}
+	own, err := r.ListSubscriptions(ctx, &other.UserID, 10, 0)
+	if err != nil {
+		return err
+	}
+	if err := sameIDs(own, []uuid.UUID{other.ID}); err != nil {
+		return fmt.Errorf("list of one user: %w", err)
+	}
+	return nil
+}
+
+func checkStream(ctx context.Context, r Repository) error {
+	ids, err := createSequence(ctx, r, 4)
+	if err != nil {
+		return err
+	}
+
+	var streamed []model.Subscription
+	collect := func(subscription model.Subscription) error {
+		streamed = append(streamed, subscription)
+		return nil
+	}
+
+	if err := r.StreamSubscriptions(ctx, nil, 0, 1, collect); err != nil {
+		return err
+	}
+	if err := sameIDs(streamed, reversed(ids)[1:]); err != nil {
+		return fmt.Errorf("no limit, offset 1: %w", err)
+	}
+
+	one, err := r.GetSubscriptionByID(ctx, ids[2])
+	if err != nil {
+		return err
+	}
+	streamed = nil
+	if err := r.StreamSubscriptions(ctx, &one.UserID, 0, 0, collect); err != nil {
+		return err
+	}
+	if err := sameIDs(streamed, []uuid.UUID{one.ID}); err != nil {
+		return fmt.Errorf("stream of one user: %w", err)
+	}
+
+	stop := errors.New("stop")
+	calls := 0
+	err = r.StreamSubscriptions(ctx, nil, 0, 0, func(model.Subscription) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		return errors.New("an error from fn does not stop the stream")
+	}
+	return nil
+}
+
+func checkActive(ctx context.Context, r Repository) error {
+	alice, bob := uuid.New(), uuid.New()
+	ended := month(2025, 2)
+
+	active1, err := r.CreateSubscription(ctx, subscription("Netflix", 599, alice, month(2025, 1)))
+	if err != nil {
+		return err
+	}
+	active2, err := r.CreateSubscription(ctx, subscription("Okko", 399, bob, month(2025, 3)))
+	if err != nil {
+		return err
+	}
+	endedSubscription := subscription("ivi", 299, alice, month(2025, 1))
+	endedSubscription.EndDate = &ended
+	if _, err := r.CreateSubscription(ctx, endedSubscription); err != nil {
+		return err
+	}
+	if _, err := r.CreateSubscription(ctx, subscription("Wink", 199, alice, month(2025, 4))); err != nil {
+		return err
+	}
+
+	at := month(2025, 3)
+	all, err := r.ListActiveSubscriptions(ctx, nil, at)
+	if err != nil {
+		return err
+	}
+	if err := sameIDSet(all, active1.ID, active2.ID); err != nil {
+		return fmt.Errorf("all users: %w", err)
+	}
+
+	onlyAlice, err := r.ListActiveSubscriptions(ctx, []uuid.UUID{alice}, at)
+	if err != nil {
+		return err
+	}
+	if err := sameIDSet(onlyAlice, active1.ID); err != nil {
+		return fmt.Errorf("one user: %w", err)
+	}
+
+	// The end month itself still counts as active.
+	atEnd, err := r.ListActiveSubscriptions(ctx, []uuid.UUID{alice}, ended)
+	if err != nil {
+		return err
+	}
+	if len(atEnd) != 2 {
+		return fmt.Errorf("active at end date: got %d subscriptions, want 2", len(atEnd))
+	}
+	return nil
+}
+
+func checkTotalCost(ctx context.Context, r Repository) error {
+	alice, bob := uuid.New(), uuid.New()
+	end := month(2025, 6)
+
+	netflix := subscription("Netflix Premium", 1000, alice, month(2025, 1))
+	netflix.EndDate = &end
+	family, err := r.CreateSubscription(ctx, subscription("Yandex Plus", 300, alice, month(2025, 2)))
+	if err != nil {
+		return err
+	}
+	for _, s := range []model.Subscription{netflix, subscription("netflix", 500, bob, month(2025, 3))} {
+		if _, err := r.CreateSubscription(ctx, s); err != nil {
+			return err
+		}
+	}
+	err = r.SetSubscriptionMembers(ctx, family.ID, []model.SubscriptionMember{
+		{UserID: alice, Share: 0.5},
+		{UserID: bob, Share: 0.5},
+	})
+	if err != nil {
+		return err
+	}
+
+	name := "NETFLIX"
+	start := month(2025, 2)
+	cases := []struct {
+		name   string
+		filter model.CostFilter
+		want   int
+	}{
+		{"no filter", model.CostFilter{}, 1800},
+		{"service name", model.CostFilter{ServiceName: &name}, 1500},
+		{"start date", model.CostFilter{StartDate: &start}, 800},
+		{"end date", model.CostFilter{EndDate: &end}, 1800},
+		{"alice", model.CostFilter{UserID: &alice}, 1150},
+		{"bob", model.CostFilter{UserID: &bob}, 650},
+		{"bob and service name", model.CostFilter{UserID: &bob, ServiceName: &name}, 500},
+	}
+
+	for _, c := range cases {
+		got, err := r.GetTotalCost(ctx, c.filter)
+		if err != nil {
+			return fmt.Errorf("%s: %w", c.name, err)
+		}
+		if got != c.want {
+			return fmt.Errorf("%s: got %d, want %d", c.name, got, c.want)
+		}
+	}
+
+	before := month(2025, 5)
+	got, err := r.GetTotalCost(ctx, model.CostFilter{EndDate: &before})
+	if err != nil {
+		return err
+	}
+	if got != 800 {
+		return fmt.Errorf("end date before the end of a subscription: got %d, want 800", got)
+	}
+	return nil
+}
+
+func checkMembers(ctx context.Context, r Repository) error {
+	created, err := r.CreateSubscription(ctx, subscription("Apple One", 900, uuid.New(), month(2025, 1)))
+	if err != nil {
+		return err
+	}
+
+	first := []model.SubscriptionMember{
+		{UserID: uuid.New(), Share: 0.25},
+		{UserID: uuid.New(), Share: 0.75},
+	}
+	if err := r.SetSubscriptionMembers(ctx, created.ID, first); err != nil {
+		return err
+	}
+	second := []model.SubscriptionMember{{UserID: uuid.New(), Share: 1}}
+	if err := r.SetSubscriptionMembers(ctx, created.ID, second); err != nil {
+		return err
+	}
+
+	members, err := r.ListSubscriptionMembers(ctx, created.ID)
+	if err != nil {
+		return err
+	}
+	if len(members) != 1 || members[0].UserID != second[0].UserID || members[0].SubscriptionID != created.ID {
+		return errors.New("members were not replaced")
+	}
+
+	if err := r.SetSubscriptionMembers(ctx, created.ID, nil); err != nil {
+		return err
+	}
+	members, err = r.ListSubscriptionMembers(ctx, created.ID)
+	if err != nil {
+		return err
+	}
+	if len(members) != 0 {
+		return errors.New("members were not cleared")
+	}
+	return nil
+}
+
+func checkTransactions(ctx context.Context, r Repository) error {
+	failure := errors.New("failure")
+	var rolledBack uuid.UUID
+	err := r.WithinTx(ctx, func(ctx context.Context) error {
+		created, err := r.CreateSubscription(ctx, subscription("Netflix", 599, uuid.New(), month(2025, 1)))
+		if err != nil {
+			return err
+		}
+		rolledBack = created.ID
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		return fmt.Errorf("WithinTx returned %v, want the error of fn", err)
+	}
+	if _, err := r.GetSubscriptionByID(ctx, rolledBack); !errors.Is(err, sql.ErrNoRows) {
+		return errors.New("a failed transaction was not rolled back")
+	}
+
+	var panicked uuid.UUID
+	func() {
+		defer func() { _ = recover() }()
+		_ = r.WithinTx(ctx, func(ctx context.Context) error {
+			created, err := r.CreateSubscription(ctx, subscription("Okko", 399, uuid.New(), month(2025, 1)))
+			if err != nil {
+				return err
+			}
+			panicked = created.ID
+			panic("boom")
+		})
+	}()
+	if _, err := r.GetSubscriptionByID(ctx, panicked); !errors.Is(err, sql.ErrNoRows) {
+		return errors.New("a panicking transaction was not rolled back")
+	}
+
+	var kept, undone uuid.UUID
+	err = r.WithinTx(ctx, func(ctx context.Context) error {
+		created, err := r.CreateSubscription(ctx, subscription("Spotify", 299, uuid.New(), month(2025, 1)))
+		if err != nil {
+			return err
+		}
+		kept = created.ID
+
+		err = r.WithinSavepoint(ctx, func(ctx context.Context) error {
+			created, err := r.CreateSubscription(ctx, subscription("ivi", 199, uuid.New(), month(2025, 1)))
+			if err != nil {
+				return err
+			}
+			undone = created.ID
+			return failure
+		})
+		if !errors.Is(err, failure) {
+			return fmt.Errorf("WithinSavepoint returned %v, want the error of fn", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := r.GetSubscriptionByID(ctx, kept); err != nil {
+		return fmt.Errorf("a committed transaction lost its changes: %w", err)
+	}
+	if _, err := r.GetSubscriptionByID(ctx, undone); !errors.Is(err, sql.ErrNoRows) {
+		return errors.New("a failed savepoint was not rolled back")
+	}
+	return nil
+}
+
+func checkEvents(ctx context.Context, r Repository) error {
+	event := model.Event{
+		ID:         uuid.New(),
+		Type:       model.EventSubscriptionCreated,
+		OccurredAt: time.Now().UTC(),
+		Payload:    []byte(`{"id":"1"}`),
+	}
+	if err := r.AddEvent(ctx, event); err != nil {
+		return err
+	}
+	if err := r.AddEvent(ctx, event); err != nil {
+		return fmt.Errorf("adding an event twice: %w", err)
+	}
+	return nil
+}
+
+// checkTenantIsolation stores data in one tenant and makes sure that another
+// tenant can neither see nor change it.
+func checkTenantIsolation(ctx context.Context, r Repository) error {
+	userID := uuid.New()
+	created, err := r.CreateSubscription(ctx, subscription("Netflix", 599, userID, month(2025, 1)))
+	if err != nil {
+		return err
+	}
+	members := []model.SubscriptionMember{{UserID: userID, Share: 1}}
+	if err := r.SetSubscriptionMembers(ctx, created.ID, members); err != nil {
+		return err
+	}
+
+	other := tenant.WithTenant(ctx, model.Tenant{ID: "repositorytest-other", DefaultCurrency: "RUB"})
+
+	if _, err := r.GetSubscriptionByID(other, created.ID); !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("get from another tenant returned %v, want sql.ErrNoRows", err)
+	}
+	if err := r.UpdateSubscription(other, created); !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("update from another tenant returned %v, want sql.ErrNoRows", err)
+	}
+	if err := r.DeleteSubscription(other, created.ID); !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("delete from another tenant returned %v, want sql.ErrNoRows", err)
+	}
+
+	listed, err := r.ListSubscriptions(other, nil, 10, 0)
+	if err != nil {
+		return err
+	}
+	active, err := r.ListActiveSubscriptions(other, nil, month(2025, 3))
+	if err != nil {
+		return err
+	}
+	var streamed int
+	err = r.StreamSubscriptions(other, nil, 0, 0, func(model.Subscription) error {
+		streamed++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(listed) != 0 || len(active) != 0 || streamed != 0 {
+		return errors.New("another tenant lists the subscriptions")
+	}
+
+	total, err := r.GetTotalCost(other, model.CostFilter{})
+	if err != nil {
+		return err
+	}
+	userTotal, err := r.GetTotalCost(other, model.CostFilter{UserID: &userID})
+	if err != nil {
+		return err
+	}
+	if total != 0 || userTotal != 0 {
+		return fmt.Errorf("another tenant counts the subscriptions: got %d and %d, want 0", total, userTotal)
+	}
+
+	otherMembers, err := r.ListSubscriptionMembers(other, created.ID)
+	if err != nil {
+		return err
+	}
+	if len(otherMembers) != 0 {
+		return errors.New("another tenant lists the members")
+	}
+
+	got, err := r.GetSubscriptionByID(ctx, created.ID)
+	if err != nil {
+		return fmt.Errorf("the subscription is gone from its own tenant: %w", err)
+	}
+	return sameSubscription(got, created)
+}
+
+func subscription(serviceName string, price int, userID uuid.UUID, startDate time.Time) model.Subscription {
+	return model.Subscription{
+		ServiceName: serviceName,
+		Price:       price,
+		UserID:      userID,
+		StartDate:   startDate,
+	}
+}
+
+// createSequence creates n subscriptions with increasing creation times and
+// returns their IDs, oldest first.
+func createSequence(ctx context.Context, r Repository, n int) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0, n)
+	for i := 0; i < n; i++ {
+		created, err := r.CreateSubscription(ctx, subscription(fmt.Sprintf("Service %d", i), 100+i, uuid.New(), month(2025, 1)))
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, created.ID)
+		time.Sleep(2 * time.Millisecond)
+	}
+	return ids, nil
+}
+
+func month(year int, m time.Month) time.Time {
+	return time.Date(year, m, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func sameSubscription(got, want model.Subscription) error {
+	if got.ID != want.ID || got.ServiceName != want.ServiceName || got.Price != want.Price || got.UserID != want.UserID {
+		return fmt.Errorf("got %+v, want %+v", got, want)
+	}
+	if !got.StartDate.Equal(want.StartDate) {
+		return fmt.Errorf("start_date: got %s, want %s", got.StartDate, want.StartDate)
+	}
+	if (got.EndDate == nil) != (want.EndDate == nil) || (got.EndDate != nil && !got.EndDate.Equal(*want.EndDate)) {
+		return fmt.Errorf("end_date: got %v, want %v", got.EndDate, want.EndDate)
+	}
+	return nil
+}
+
+func sameIDs(subscriptions []model.Subscription, ids []uuid.UUID) error {
+	if len(subscriptions) != len(ids) {
+		return fmt.Errorf("got %d subscriptions, want %d", len(subscriptions), len(ids))
+	}
+	for i, subscription := range subscriptions {
+		if subscription.ID != ids[i] {
+			return fmt.Errorf("position %d: got %s, want %s", i, subscription.ID, ids[i])
+		}
+	}
+	return nil
+}
+
+func sameIDSet(subscriptions []model.Subscription, ids ...uuid.UUID) error {
+	want := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		want[id] = true
+	}
+	if len(subscriptions) != len(ids) {
+		return fmt.Errorf("got %d subscriptions, want %d", len(subscriptions), len(ids))
+	}
+	for _, subscription := range subscriptions {
+		if !want[subscription.ID] {
+			return fmt.Errorf("unexpected subscription %s", subscription.ID)
+		}
+	}
+	return nil
+}
+
+func reversed(ids []uuid.UUID) []uuid.UUID {
+	out := make([]uuid.UUID, len(ids))
+	for i, id := range ids {
+		out[len(ids)-1-i] = id
+	}
+	return out
+}
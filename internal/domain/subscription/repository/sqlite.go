@@ -0,0 +1,343 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/infra/database"
+	"github.com/golangtestcases/subscribe-service/internal/infra/tenant"
+	"github.com/google/uuid"
+	"modernc.org/sqlite"
+)
+
+// sqliteTimeLayout stores times in UTC with the microsecond precision of a
+// PostgreSQL TIMESTAMP. Every value has the same width, so comparing the
+// strings compares the times.
+const sqliteTimeLayout = "2006-01-02 15:04:05.000000"
+
+func init() {
+	// casefold lower-cases Unicode text, which the built-in lower() only does
+	// for ASCII. It stands in for ILIKE.
+	sqlite.MustRegisterDeterministicScalarFunction("casefold", 1, func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		switch v := args[0].(type) {
+		case string:
+			return strings.ToLower(v), nil
+		case []byte:
+			return strings.ToLower(string(v)), nil
+		default:
+			return v, nil
+		}
+	})
+}
+
+// SQLiteRepository stores subscriptions in SQLite with the same semantics as
+// PostgreSQLRepository, including the tenant scope of every query. UUIDs are
+// kept as text and times as UTC text in sqliteTimeLayout. Use it with a
+// database.Transactor on the same database.
+type SQLiteRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteRepository(db *sql.DB) *SQLiteRepository {
+	return &SQLiteRepository{db: db}
+}
+
+// conn returns the transaction carried by ctx, if any, or the connection pool.
+func (r *SQLiteRepository) conn(ctx context.Context) database.Querier {
+	return database.Conn(ctx, r.db)
+}
+
+func (r *SQLiteRepository) CreateSubscription(ctx context.Context, subscription model.Subscription) (model.Subscription, error) {
+	subscription.ID = uuid.New()
+	subscription.CreatedAt = time.Now()
+	subscription.UpdatedAt = time.Now()
+
+	query := `
+		INSERT INTO subscriptions (id, tenant_id, service_name, price, user_id, start_date, end_date, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.conn(ctx).ExecContext(ctx, query,
+		subscription.ID, tenant.ID(ctx), subscription.ServiceName, subscription.Price,
+		subscription.UserID, sqliteTime(subscription.StartDate), sqliteNullTime(subscription.EndDate),
+		sqliteTime(subscription.CreatedAt), sqliteTime(subscription.UpdatedAt),
+	)
+
+	return subscription, err
+}
+
+func (r *SQLiteRepository) GetSubscriptionByID(ctx context.Context, id uuid.UUID) (model.Subscription, error) {
+	var subscription model.Subscription
+	query := `
+		SELECT id, service_name, price, user_id, start_date, end_date, created_at, updated_at
+		FROM subscriptions WHERE tenant_id = ? AND id = ?
+	`
+
+	err := r.conn(ctx).QueryRowContext(ctx, query, tenant.ID(ctx), id).Scan(
+		&subscription.ID, &subscription.ServiceName, &subscription.Price,
+		&subscription.UserID, &subscription.StartDate, &subscription.EndDate,
+		&subscription.CreatedAt, &subscription.UpdatedAt,
+	)
+
+	return subscription, err
+}
+
+func (r *SQLiteRepository) UpdateSubscription(ctx context.Context, subscription model.Subscription) error {
+	subscription.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE subscriptions
+		SET service_name = ?, price = ?, user_id = ?, start_date = ?, end_date = ?, updated_at = ?
+		WHERE tenant_id = ? AND id = ?
+	`
+
+	result, err := r.conn(ctx).ExecContext(ctx, query,
+		subscription.ServiceName, subscription.Price, subscription.UserID,
+		sqliteTime(subscription.StartDate), sqliteNullTime(subscription.EndDate),
+		sqliteTime(subscription.UpdatedAt), tenant.ID(ctx), subscription.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return checkRowsAffected(result)
+}
+
+func (r *SQLiteRepository) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM subscriptions WHERE tenant_id = ? AND id = ?`
+	result, err := r.conn(ctx).ExecContext(ctx, query, tenant.ID(ctx), id)
+	if err != nil {
+		return err
+	}
+
+	return checkRowsAffected(result)
+}
+
+func (r *SQLiteRepository) ListSubscriptions(ctx context.Context, userID *uuid.UUID, limit, offset int) ([]model.Subscription, error) {
+	query := `
+		SELECT id, service_name, price, user_id, start_date, end_date, created_at, updated_at
+		FROM subscriptions WHERE tenant_id = ?
+	`
+	args := []interface{}{tenant.ID(ctx)}
+
+	if userID != nil {
+		query += " AND user_id = ?"
+		args = append(args, *userID)
+	}
+
+	query += " ORDER BY created_at DESC LIMIT ? OFFSET ?"
+	args = append(args, limit, offset)
+
+	return r.querySubscriptions(ctx, query, args...)
+}
+
+// StreamSubscriptions calls fn for each subscription in the same order as
+// ListSubscriptions, reading rows from the database as it goes instead of
+// collecting them first. A nil userID means subscriptions of all users and a
+// limit of zero means no limit.
+func (r *SQLiteRepository) StreamSubscriptions(ctx context.Context, userID *uuid.UUID, limit, offset int, fn func(model.Subscription) error) error {
+	query := `
+		SELECT id, service_name, price, user_id, start_date, end_date, created_at, updated_at
+		FROM subscriptions WHERE tenant_id = ?
+	`
+	args := []interface{}{tenant.ID(ctx)}
+
+	if userID != nil {
+		query += " AND user_id = ?"
+		args = append(args, *userID)
+	}
+
+	// A negative LIMIT means no limit in SQLite.
+	if limit <= 0 {
+		limit = -1
+	}
+	query += " ORDER BY created_at DESC LIMIT ? OFFSET ?"
+	args = append(args, limit, offset)
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var subscription model.Subscription
+		err := rows.Scan(
+			&subscription.ID, &subscription.ServiceName, &subscription.Price,
+			&subscription.UserID, &subscription.StartDate, &subscription.EndDate,
+			&subscription.CreatedAt, &subscription.UpdatedAt,
+		)
+		if err != nil {
+			return err
+		}
+		if err := fn(subscription); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// ListActiveSubscriptions returns the subscriptions active at the given moment.
+// An empty userIDs slice means subscriptions of all users.
+func (r *SQLiteRepository) ListActiveSubscriptions(ctx context.Context, userIDs []uuid.UUID, at time.Time) ([]model.Subscription, error) {
+	query := `
+		SELECT id, service_name, price, user_id, start_date, end_date, created_at, updated_at
+		FROM subscriptions
+		WHERE tenant_id = ? AND start_date <= ? AND (end_date IS NULL OR end_date >= ?)
+	`
+	args := []interface{}{tenant.ID(ctx), sqliteTime(at), sqliteTime(at)}
+
+	if len(userIDs) > 0 {
+		query += " AND user_id IN (?" + strings.Repeat(", ?", len(userIDs)-1) + ")"
+		for _, userID := range userIDs {
+			args = append(args, userID)
+		}
+	}
+
+	query += " ORDER BY user_id, created_at"
+
+	return r.querySubscriptions(ctx, query, args...)
+}
+
+// GetTotalCost sums subscription prices matching the filter, counting shared
+// subscriptions the same way as PostgreSQLRepository.GetTotalCost. The service
+// name is matched case-insensitively with casefold in place of ILIKE.
+func (r *SQLiteRepository) GetTotalCost(ctx context.Context, filter model.CostFilter) (int, error) {
+	query := `SELECT COALESCE(SUM(s.price), 0) FROM subscriptions s WHERE s.tenant_id = ?`
+	args := []interface{}{tenant.ID(ctx)}
+
+	if filter.UserID != nil {
+		query = `
+			SELECT CAST(COALESCE(ROUND(SUM(s.price * COALESCE(m.share, 1))), 0) AS INTEGER)
+			FROM subscriptions s
+			LEFT JOIN subscription_members m ON m.subscription_id = s.id AND m.user_id = ?
+			WHERE s.tenant_id = ? AND (m.user_id IS NOT NULL OR (s.user_id = ? AND NOT EXISTS (
+				SELECT 1 FROM subscription_members sm WHERE sm.subscription_id = s.id
+			)))`
+		args = []interface{}{*filter.UserID, tenant.ID(ctx), *filter.UserID}
+	}
+
+	if filter.ServiceName != nil {
+		query += ` AND casefold(s.service_name) LIKE casefold(?) ESCAPE '\'`
+		args = append(args, "%"+*filter.ServiceName+"%")
+	}
+
+	if filter.StartDate != nil {
+		query += " AND s.start_date >= ?"
+		args = append(args, sqliteTime(*filter.StartDate))
+	}
+
+	if filter.EndDate != nil {
+		query += " AND (s.end_date IS NULL OR s.end_date <= ?)"
+		args = append(args, sqliteTime(*filter.EndDate))
+	}
+
+	var totalCost int
+	err := r.conn(ctx).QueryRowContext(ctx, query, args...).Scan(&totalCost)
+	return totalCost, err
+}
+
+func (r *SQLiteRepository) ListSubscriptionMembers(ctx context.Context, subscriptionID uuid.UUID) ([]model.SubscriptionMember, error) {
+	query := `
+		SELECT subscription_id, user_id, share
+		FROM subscription_members WHERE tenant_id = ? AND subscription_id = ? ORDER BY user_id
+	`
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, tenant.ID(ctx), subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var members []model.SubscriptionMember
+	for rows.Next() {
+		var member model.SubscriptionMember
+		if err := rows.Scan(&member.SubscriptionID, &member.UserID, &member.Share); err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+
+	return members, rows.Err()
+}
+
+// SetSubscriptionMembers replaces all members of the subscription. Shares are
+// kept with four decimal places, like the NUMERIC(5, 4) column in PostgreSQL.
+// It should run within a transaction so that a failure does not leave the
+// members half-replaced.
+func (r *SQLiteRepository) SetSubscriptionMembers(ctx context.Context, subscriptionID uuid.UUID, members []model.SubscriptionMember) error {
+	query := `DELETE FROM subscription_members WHERE tenant_id = ? AND subscription_id = ?`
+	if _, err := r.conn(ctx).ExecContext(ctx, query, tenant.ID(ctx), subscriptionID); err != nil {
+		return err
+	}
+
+	for _, member := range members {
+		query := `INSERT INTO subscription_members (tenant_id, subscription_id, user_id, share) VALUES (?, ?, ?, ?)`
+		share := math.Round(member.Share*10000) / 10000
+		if _, err := r.conn(ctx).ExecContext(ctx, query, tenant.ID(ctx), subscriptionID, member.UserID, share); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddEvent writes the event to the outbox. Called with the context of a
+// transaction, the event is stored only if the change it describes is
+// committed. An event whose ID is already in the outbox is ignored.
+func (r *SQLiteRepository) AddEvent(ctx context.Context, event model.Event) error {
+	now := sqliteTime(time.Now())
+
+	query := `
+		INSERT INTO outbox (id, tenant_id, event_type, payload, occurred_at, next_attempt_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO NOTHING
+	`
+
+	_, err := r.conn(ctx).ExecContext(ctx, query,
+		event.ID, tenant.ID(ctx), event.Type, string(event.Payload), sqliteTime(event.OccurredAt), now, now,
+	)
+
+	return err
+}
+
+func (r *SQLiteRepository) querySubscriptions(ctx context.Context, query string, args ...interface{}) ([]model.Subscription, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subscriptions []model.Subscription
+	for rows.Next() {
+		var subscription model.Subscription
+		err := rows.Scan(
+			&subscription.ID, &subscription.ServiceName, &subscription.Price,
+			&subscription.UserID, &subscription.StartDate, &subscription.EndDate,
+			&subscription.CreatedAt, &subscription.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	return subscriptions, rows.Err()
+}
+
+func sqliteTime(t time.Time) string {
+	return t.UTC().Format(sqliteTimeLayout)
+}
+
+func sqliteNullTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return sqliteTime(*t)
+}
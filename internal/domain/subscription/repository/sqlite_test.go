@@ -0,0 +1,69 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/sqlite"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/subscription/repository"
+	"github.com/golangtestcases/subscribe-service/internal/domain/subscription/repository/repositorytest"
+	"github.com/golangtestcases/subscribe-service/internal/domain/subscription/service"
+	"github.com/golangtestcases/subscribe-service/internal/infra/database"
+)
+
+// transacted combines a repository with a database.Transactor on the same
+// database, as the app does.
+type transacted struct {
+	outboxRepository
+	*database.Transactor
+}
+
+type outboxRepository interface {
+	service.SubscriptionRepository
+	service.EventRecorder
+}
+
+func TestSQLiteRepository(t *testing.T) {
+	dir := t.TempDir()
+	var dbs []*sql.DB
+	t.Cleanup(func() {
+		for _, db := range dbs {
+			db.Close()
+		}
+	})
+
+	err := repositorytest.TestRepository(context.Background(), func() (repositorytest.Repository, error) {
+		path := filepath.Join(dir, fmt.Sprintf("%d.db", len(dbs)))
+		db, err := sql.Open("sqlite", "file:"+path+"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_txlock=immediate")
+		if err != nil {
+			return nil, err
+		}
+		dbs = append(dbs, db)
+
+		if err := migrateSQLite(db); err != nil {
+			return nil, err
+		}
+		return transacted{repository.NewSQLiteRepository(db), database.NewTransactor(db)}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func migrateSQLite(db *sql.DB) error {
+	driver, err := sqlite.WithInstance(db, &sqlite.Config{})
+	if err != nil {
+		return err
+	}
+	m, err := migrate.NewWithDatabaseInstance("file://../../../../migrations/sqlite", "sqlite", driver)
+	if err != nil {
+		return err
+	}
+	return m.Up()
+}
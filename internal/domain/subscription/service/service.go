@@ -2,10 +2,19 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"time"
 
+	"github.com/golangtestcases/subscribe-service/internal/domain/access"
 	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/domain/subscription/canonical"
+	"github.com/golangtestcases/subscribe-service/internal/domain/subscription/detector"
 	"github.com/google/uuid"
 )
 
@@ -14,46 +23,101 @@ type SubscriptionRepository interface {
 	GetSubscriptionByID(context.Context, uuid.UUID) (model.Subscription, error)
 	UpdateSubscription(context.Context, model.Subscription) error
 	DeleteSubscription(context.Context, uuid.UUID) error
-	ListSubscriptions(context.Context, int, int) ([]model.Subscription, error)
+	ListSubscriptions(context.Context, *uuid.UUID, int, int) ([]model.Subscription, error)
 	GetTotalCost(context.Context, model.CostFilter) (int, error)
+	StreamSubscriptions(context.Context, *uuid.UUID, int, int, func(model.Subscription) error) error
+	ListActiveSubscriptions(context.Context, []uuid.UUID, time.Time) ([]model.Subscription, error)
+	ListSubscriptionMembers(context.Context, uuid.UUID) ([]model.SubscriptionMember, error)
+	SetSubscriptionMembers(context.Context, uuid.UUID, []model.SubscriptionMember) error
 }
 
+// Transactor makes a unit of work atomic. Repository calls made with the
+// context passed to fn run in the same transaction, which is committed when fn
+// returns nil and rolled back when it fails or panics.
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+	// WithinSavepoint lets fn fail without aborting the surrounding
+	// transaction; only the changes made by fn are undone.
+	WithinSavepoint(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type BudgetEvaluator interface {
+	EvaluateBudgets(ctx context.Context, userID uuid.UUID) error
+}
+
+// EventRecorder writes events to the outbox. Called with the context of the
+// transaction making the change, it must take part in that transaction.
+type EventRecorder interface {
+	AddEvent(ctx context.Context, event model.Event) error
+}
+
+// renewalEventNamespace derives renewal.upcoming event IDs from the
+// subscription and renewal date, so that announcing the same renewal again
+// produces the same event.
+var renewalEventNamespace = uuid.MustParse("6f0c7f1e-3f5a-4d8e-9a51-2b7d0c4e8a93")
+
 type SubscriptionService struct {
 	subscriptionRepository SubscriptionRepository
+	transactor             Transactor
+	budgetEvaluator        BudgetEvaluator
+	eventRecorder          EventRecorder
+	logger                 *slog.Logger
 }
 
-func NewSubscriptionService(subscriptionRepository SubscriptionRepository) *SubscriptionService {
-	return &SubscriptionService{subscriptionRepository: subscriptionRepository}
+func NewSubscriptionService(subscriptionRepository SubscriptionRepository, transactor Transactor, budgetEvaluator BudgetEvaluator, eventRecorder EventRecorder, logger *slog.Logger) *SubscriptionService {
+	return &SubscriptionService{
+		subscriptionRepository: subscriptionRepository,
+		transactor:             transactor,
+		budgetEvaluator:        budgetEvaluator,
+		eventRecorder:          eventRecorder,
+		logger:                 logger,
+	}
+}
+
+// ValidateSubscription checks the subscription the same way CreateSubscription
+// does, without writing anything.
+func (s *SubscriptionService) ValidateSubscription(subscription model.Subscription) error {
+	return validateSubscription(subscription)
 }
 
 func (s *SubscriptionService) CreateSubscription(ctx context.Context, subscription model.Subscription) (model.Subscription, error) {
-	if subscription.ServiceName == "" {
-		return model.Subscription{}, errors.New("service_name is required")
-	}
-	if subscription.Price <= 0 {
-		return model.Subscription{}, errors.New("price must be positive")
-	}
-	if subscription.UserID == uuid.Nil {
-		return model.Subscription{}, errors.New("user_id is required")
+	if err := validateSubscription(subscription); err != nil {
+		return model.Subscription{}, err
 	}
-	if subscription.StartDate.IsZero() {
-		return model.Subscription{}, errors.New("start_date is required")
+	if err := access.CheckAccess(ctx, subscription.UserID); err != nil {
+		return model.Subscription{}, err
 	}
 
-	newSubscription, err := s.subscriptionRepository.CreateSubscription(ctx, subscription)
+	var newSubscription model.Subscription
+	err := s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		newSubscription, err = s.subscriptionRepository.CreateSubscription(ctx, subscription)
+		if err != nil {
+			return fmt.Errorf("subscriptionRepository.CreateSubscription: %w", err)
+		}
+		return s.recordEvent(ctx, model.EventSubscriptionCreated, newSubscription)
+	})
 	if err != nil {
-		return model.Subscription{}, fmt.Errorf("subscriptionRepository.CreateSubscription: %w", err)
+		return model.Subscription{}, err
 	}
 
+	s.evaluateBudgets(ctx, newSubscription.UserID)
+
 	return newSubscription, nil
 }
 
+// GetSubscriptionByID returns the subscription. Subscriptions of other users
+// are reported as sql.ErrNoRows to callers restricted to their own, so that
+// they cannot tell them from missing ones.
 func (s *SubscriptionService) GetSubscriptionByID(ctx context.Context, id uuid.UUID) (model.Subscription, error) {
 	if id == uuid.Nil {
-		return model.Subscription{}, errors.New("id is required")
+		return model.Subscription{}, model.NewValidationError("id is required")
 	}
 
 	subscription, err := s.subscriptionRepository.GetSubscriptionByID(ctx, id)
+	if err == nil && !access.CanAccess(ctx, subscription.UserID) {
+		err = sql.ErrNoRows
+	}
 	if err != nil {
 		return model.Subscription{}, fmt.Errorf("subscriptionRepository.GetSubscriptionByID: %w", err)
 	}
@@ -63,24 +127,35 @@ func (s *SubscriptionService) GetSubscriptionByID(ctx context.Context, id uuid.U
 
 func (s *SubscriptionService) UpdateSubscription(ctx context.Context, subscription model.Subscription) error {
 	if subscription.ID == uuid.Nil {
-		return errors.New("id is required")
-	}
-	if subscription.ServiceName == "" {
-		return errors.New("service_name is required")
-	}
-	if subscription.Price <= 0 {
-		return errors.New("price must be positive")
+		return model.NewValidationError("id is required")
 	}
-	if subscription.UserID == uuid.Nil {
-		return errors.New("user_id is required")
+	if err := validateSubscription(subscription); err != nil {
+		return err
 	}
-	if subscription.StartDate.IsZero() {
-		return errors.New("start_date is required")
+	if err := access.CheckAccess(ctx, subscription.UserID); err != nil {
+		return err
 	}
 
-	err := s.subscriptionRepository.UpdateSubscription(ctx, subscription)
+	var previous model.Subscription
+	err := s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if previous, err = s.getOwned(ctx, subscription.ID); err != nil {
+			return err
+		}
+		if err := s.subscriptionRepository.UpdateSubscription(ctx, subscription); err != nil {
+			return fmt.Errorf("subscriptionRepository.UpdateSubscription: %w", err)
+		}
+		return s.recordEvent(ctx, model.EventSubscriptionUpdated, subscription)
+	})
 	if err != nil {
-		return fmt.Errorf("subscriptionRepository.UpdateSubscription: %w", err)
+		return err
+	}
+
+	// A subscription handed to another user leaves the budgets of the
+	// previous one.
+	s.evaluateBudgets(ctx, subscription.UserID)
+	if previous.UserID != subscription.UserID {
+		s.evaluateBudgets(ctx, previous.UserID)
 	}
 
 	return nil
@@ -88,17 +163,23 @@ func (s *SubscriptionService) UpdateSubscription(ctx context.Context, subscripti
 
 func (s *SubscriptionService) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
 	if id == uuid.Nil {
-		return errors.New("id is required")
+		return model.NewValidationError("id is required")
 	}
 
-	err := s.subscriptionRepository.DeleteSubscription(ctx, id)
-	if err != nil {
-		return fmt.Errorf("subscriptionRepository.DeleteSubscription: %w", err)
-	}
-
-	return nil
+	return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		subscription, err := s.getOwned(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.subscriptionRepository.DeleteSubscription(ctx, id); err != nil {
+			return fmt.Errorf("subscriptionRepository.DeleteSubscription: %w", err)
+		}
+		return s.recordEvent(ctx, model.EventSubscriptionDeleted, subscription)
+	})
 }
 
+// ListSubscriptions returns a page of all subscriptions, newest first, or of
+// the caller's own if it is restricted to them.
 func (s *SubscriptionService) ListSubscriptions(ctx context.Context, limit, offset int) ([]model.Subscription, error) {
 	if limit <= 0 {
 		limit = 10
@@ -107,7 +188,12 @@ func (s *SubscriptionService) ListSubscriptions(ctx context.Context, limit, offs
 		offset = 0
 	}
 
-	subscriptions, err := s.subscriptionRepository.ListSubscriptions(ctx, limit, offset)
+	var userID *uuid.UUID
+	if restricted, ok := access.RestrictedUser(ctx); ok {
+		userID = &restricted
+	}
+
+	subscriptions, err := s.subscriptionRepository.ListSubscriptions(ctx, userID, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("subscriptionRepository.ListSubscriptions: %w", err)
 	}
@@ -115,7 +201,76 @@ func (s *SubscriptionService) ListSubscriptions(ctx context.Context, limit, offs
 	return subscriptions, nil
 }
 
+// ListActiveSubscriptions returns the user's subscriptions that are active now.
+func (s *SubscriptionService) ListActiveSubscriptions(ctx context.Context, userID uuid.UUID) ([]model.Subscription, error) {
+	if userID == uuid.Nil {
+		return nil, model.NewValidationError("user_id is required")
+	}
+	if err := access.CheckAccess(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	subscriptions, err := s.subscriptionRepository.ListActiveSubscriptions(ctx, []uuid.UUID{userID}, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("subscriptionRepository.ListActiveSubscriptions: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+// GetSubscriptionStats counts the subscriptions active now, the users paying
+// for them and the monthly recurring spend across all users.
+func (s *SubscriptionService) GetSubscriptionStats(ctx context.Context) (model.SubscriptionStats, error) {
+	subscriptions, err := s.subscriptionRepository.ListActiveSubscriptions(ctx, nil, time.Now())
+	if err != nil {
+		return model.SubscriptionStats{}, fmt.Errorf("subscriptionRepository.ListActiveSubscriptions: %w", err)
+	}
+
+	stats := model.SubscriptionStats{ActiveSubscriptions: len(subscriptions)}
+	users := make(map[uuid.UUID]bool)
+	for _, subscription := range subscriptions {
+		stats.MonthlySpend += subscription.Price
+		users[subscription.UserID] = true
+	}
+	stats.ActiveUsers = len(users)
+
+	return stats, nil
+}
+
+// ExportSubscriptions passes every subscription in the page to fn without
+// loading the page into memory, or only the caller's own if it is restricted
+// to them. A limit of zero exports everything after offset.
+func (s *SubscriptionService) ExportSubscriptions(ctx context.Context, limit, offset int, fn func(model.Subscription) error) error {
+	var userID *uuid.UUID
+	if restricted, ok := access.RestrictedUser(ctx); ok {
+		userID = &restricted
+	}
+
+	if limit < 0 {
+		limit = 0
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	err := s.subscriptionRepository.StreamSubscriptions(ctx, userID, limit, offset, fn)
+	if err != nil {
+		return fmt.Errorf("subscriptionRepository.StreamSubscriptions: %w", err)
+	}
+
+	return nil
+}
+
+// GetTotalCost sums the prices of the subscriptions matching the filter. For
+// callers restricted to their own data the filter defaults to their user.
 func (s *SubscriptionService) GetTotalCost(ctx context.Context, filter model.CostFilter) (int, error) {
+	if restricted, ok := access.RestrictedUser(ctx); ok {
+		if filter.UserID != nil && *filter.UserID != restricted {
+			return 0, access.CheckAccess(ctx, *filter.UserID)
+		}
+		filter.UserID = &restricted
+	}
+
 	totalCost, err := s.subscriptionRepository.GetTotalCost(ctx, filter)
 	if err != nil {
 		return 0, fmt.Errorf("subscriptionRepository.GetTotalCost: %w", err)
@@ -123,3 +278,571 @@ func (s *SubscriptionService) GetTotalCost(ctx context.Context, filter model.Cos
 
 	return totalCost, nil
 }
+
+func (s *SubscriptionService) ListSubscriptionMembers(ctx context.Context, subscriptionID uuid.UUID) ([]model.SubscriptionMember, error) {
+	if _, err := s.GetSubscriptionByID(ctx, subscriptionID); err != nil {
+		return nil, err
+	}
+
+	members, err := s.subscriptionRepository.ListSubscriptionMembers(ctx, subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("subscriptionRepository.ListSubscriptionMembers: %w", err)
+	}
+
+	return members, nil
+}
+
+// SetSubscriptionMembers replaces the members sharing the subscription. The
+// shares must add up to 1; an empty list makes the payer the only user again.
+func (s *SubscriptionService) SetSubscriptionMembers(ctx context.Context, subscriptionID uuid.UUID, members []model.SubscriptionMember) error {
+	seen := make(map[uuid.UUID]bool)
+	total := 0.0
+	for _, member := range members {
+		if member.UserID == uuid.Nil {
+			return model.NewValidationError("user_id is required")
+		}
+		if seen[member.UserID] {
+			return model.NewValidationError("user %s is listed more than once", member.UserID)
+		}
+		if member.Share <= 0 || member.Share > 1 {
+			return model.NewValidationError("share must be greater than 0 and at most 1")
+		}
+		seen[member.UserID] = true
+		total += member.Share
+	}
+	if len(members) > 0 && math.Abs(total-1) > 0.0001 {
+		return model.NewValidationError("shares must add up to 1")
+	}
+
+	if _, err := s.GetSubscriptionByID(ctx, subscriptionID); err != nil {
+		return err
+	}
+
+	return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.subscriptionRepository.SetSubscriptionMembers(ctx, subscriptionID, members); err != nil {
+			return fmt.Errorf("subscriptionRepository.SetSubscriptionMembers: %w", err)
+		}
+		return nil
+	})
+}
+
+// ImportSubscriptions creates all subscriptions in one transaction, so either
+// every one of them is stored or none is. With dryRun nothing is written and
+// only validation runs.
+func (s *SubscriptionService) ImportSubscriptions(ctx context.Context, subscriptions []model.Subscription, dryRun bool) ([]model.Subscription, error) {
+	for i, subscription := range subscriptions {
+		if err := validateSubscription(subscription); err != nil {
+			return nil, fmt.Errorf("subscription %d: %w", i, err)
+		}
+		if err := access.CheckAccess(ctx, subscription.UserID); err != nil {
+			return nil, fmt.Errorf("subscription %d: %w", i, err)
+		}
+	}
+	if dryRun {
+		return nil, nil
+	}
+
+	imported := make([]model.Subscription, 0, len(subscriptions))
+	err := s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		for _, subscription := range subscriptions {
+			newSubscription, err := s.subscriptionRepository.CreateSubscription(ctx, subscription)
+			if err != nil {
+				return fmt.Errorf("subscriptionRepository.CreateSubscription: %w", err)
+			}
+			if err := s.recordEvent(ctx, model.EventSubscriptionCreated, newSubscription); err != nil {
+				return err
+			}
+			imported = append(imported, newSubscription)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	evaluated := make(map[uuid.UUID]bool)
+	for _, subscription := range imported {
+		if !evaluated[subscription.UserID] {
+			evaluated[subscription.UserID] = true
+			s.evaluateBudgets(ctx, subscription.UserID)
+		}
+	}
+
+	return imported, nil
+}
+
+// ExecuteBatch runs the operations in one database transaction and reports
+// the outcome of each. In atomic mode the first failure rolls the whole batch
+// back and model.ErrBatchRolledBack is returned; otherwise every operation runs in
+// its own savepoint and the successful ones are committed.
+func (s *SubscriptionService) ExecuteBatch(ctx context.Context, operations []model.BatchOperation, atomic bool) ([]model.BatchResult, error) {
+	results := make([]model.BatchResult, len(operations))
+	// previousOwners are the users updated subscriptions belonged to before,
+	// whose budgets change too.
+	previousOwners := make([]uuid.UUID, len(operations))
+	invalid := false
+	for i, operation := range operations {
+		results[i] = model.BatchResult{Type: operation.Type, Status: model.BatchStatusSkipped}
+		err := validateBatchOperation(operation)
+		if err == nil {
+			err = checkBatchAccess(ctx, operation)
+		}
+		if err != nil {
+			results[i].Status = model.BatchStatusFailed
+			results[i].Err = err
+			invalid = true
+		}
+	}
+	if atomic && invalid {
+		return results, model.ErrBatchRolledBack
+	}
+
+	err := s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		for i, operation := range operations {
+			if results[i].Status == model.BatchStatusFailed {
+				continue
+			}
+
+			run := func(ctx context.Context) error {
+				subscription, previousOwner, err := s.executeBatchOperation(ctx, operation)
+				results[i].Subscription = subscription
+				previousOwners[i] = previousOwner
+				if err != nil {
+					return err
+				}
+				return s.recordEvent(ctx, batchEventTypes[operation.Type], subscription)
+			}
+
+			var err error
+			if atomic {
+				err = run(ctx)
+			} else {
+				err = s.transactor.WithinSavepoint(ctx, run)
+			}
+			if err != nil {
+				results[i].Status = model.BatchStatusFailed
+				results[i].Err = err
+				if atomic {
+					return model.ErrBatchRolledBack
+				}
+				continue
+			}
+			results[i].Status = model.BatchStatusSucceeded
+		}
+		return nil
+	})
+	if err != nil {
+		for i := range results {
+			if results[i].Status == model.BatchStatusSucceeded {
+				results[i].Status = model.BatchStatusRolledBack
+			}
+		}
+		if errors.Is(err, model.ErrBatchRolledBack) {
+			return results, err
+		}
+		return results, fmt.Errorf("transactor.WithinTx: %w", err)
+	}
+
+	evaluated := make(map[uuid.UUID]bool)
+	for i, result := range results {
+		if result.Status != model.BatchStatusSucceeded {
+			continue
+		}
+		for _, userID := range []uuid.UUID{result.Subscription.UserID, previousOwners[i]} {
+			if userID == uuid.Nil || evaluated[userID] {
+				continue
+			}
+			evaluated[userID] = true
+			s.evaluateBudgets(ctx, userID)
+		}
+	}
+
+	return results, nil
+}
+
+// executeBatchOperation returns the subscription the operation wrote and, for
+// updates, the user it belonged to before.
+func (s *SubscriptionService) executeBatchOperation(ctx context.Context, operation model.BatchOperation) (model.Subscription, uuid.UUID, error) {
+	switch operation.Type {
+	case model.BatchOperationCreate:
+		subscription, err := s.subscriptionRepository.CreateSubscription(ctx, operation.Subscription)
+		if err != nil {
+			return model.Subscription{}, uuid.Nil, fmt.Errorf("subscriptionRepository.CreateSubscription: %w", err)
+		}
+		return subscription, uuid.Nil, nil
+	case model.BatchOperationUpdate:
+		subscription := operation.Subscription
+		subscription.ID = operation.ID
+		previous, err := s.getOwned(ctx, subscription.ID)
+		if err != nil {
+			return model.Subscription{}, uuid.Nil, err
+		}
+		if err := s.subscriptionRepository.UpdateSubscription(ctx, subscription); err != nil {
+			return model.Subscription{}, uuid.Nil, fmt.Errorf("subscriptionRepository.UpdateSubscription: %w", err)
+		}
+		return subscription, previous.UserID, nil
+	default:
+		subscription, err := s.getOwned(ctx, operation.ID)
+		if err != nil {
+			return model.Subscription{}, uuid.Nil, err
+		}
+		if err := s.subscriptionRepository.DeleteSubscription(ctx, operation.ID); err != nil {
+			return model.Subscription{}, uuid.Nil, fmt.Errorf("subscriptionRepository.DeleteSubscription: %w", err)
+		}
+		return subscription, uuid.Nil, nil
+	}
+}
+
+// FindDuplicates groups currently active subscriptions to the same canonical
+// service. With no user IDs every user is checked separately; with several user
+// IDs they are treated as one group, e.g. a family, and duplicates across
+// members are reported too.
+func (s *SubscriptionService) FindDuplicates(ctx context.Context, userIDs []uuid.UUID) ([]model.DuplicateGroup, error) {
+	if restricted, ok := access.RestrictedUser(ctx); ok && len(userIDs) == 0 {
+		userIDs = []uuid.UUID{restricted}
+	}
+	for _, userID := range userIDs {
+		if err := access.CheckAccess(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	subscriptions, err := s.subscriptionRepository.ListActiveSubscriptions(ctx, userIDs, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("subscriptionRepository.ListActiveSubscriptions: %w", err)
+	}
+
+	acrossUsers := len(userIDs) > 1
+
+	groupsByKey := make(map[string]*model.DuplicateGroup)
+	var keys []string
+	for _, subscription := range subscriptions {
+		name := canonical.Name(subscription.ServiceName)
+		key := name
+		if !acrossUsers {
+			key = subscription.UserID.String() + "/" + name
+		}
+
+		group, ok := groupsByKey[key]
+		if !ok {
+			group = &model.DuplicateGroup{ServiceName: name}
+			groupsByKey[key] = group
+			keys = append(keys, key)
+		}
+		group.Subscriptions = append(group.Subscriptions, subscription)
+	}
+
+	var groups []model.DuplicateGroup
+	for _, key := range keys {
+		group := groupsByKey[key]
+		if len(group.Subscriptions) < 2 {
+			continue
+		}
+
+		maxPrice := 0
+		seenUsers := make(map[uuid.UUID]bool)
+		for _, subscription := range group.Subscriptions {
+			group.MonthlySpend += subscription.Price
+			maxPrice = max(maxPrice, subscription.Price)
+			if !seenUsers[subscription.UserID] {
+				seenUsers[subscription.UserID] = true
+				group.UserIDs = append(group.UserIDs, subscription.UserID)
+			}
+		}
+		group.WastedSpend = group.MonthlySpend - maxPrice
+
+		groups = append(groups, *group)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].WastedSpend > groups[j].WastedSpend
+	})
+
+	return groups, nil
+}
+
+// FindDuplicatesOf returns the user's other subscriptions to the same canonical
+// service that are active when the given subscription starts.
+func (s *SubscriptionService) FindDuplicatesOf(ctx context.Context, subscription model.Subscription) ([]model.Subscription, error) {
+	at := time.Now()
+	if subscription.StartDate.After(at) || (subscription.EndDate != nil && subscription.EndDate.Before(at)) {
+		at = subscription.StartDate
+	}
+
+	subscriptions, err := s.subscriptionRepository.ListActiveSubscriptions(ctx, []uuid.UUID{subscription.UserID}, at)
+	if err != nil {
+		return nil, fmt.Errorf("subscriptionRepository.ListActiveSubscriptions: %w", err)
+	}
+
+	name := canonical.Name(subscription.ServiceName)
+
+	var duplicates []model.Subscription
+	for _, existing := range subscriptions {
+		if existing.ID == subscription.ID || canonical.Name(existing.ServiceName) != name {
+			continue
+		}
+		duplicates = append(duplicates, existing)
+	}
+
+	return duplicates, nil
+}
+
+// FindPriceAnomalies compares the price of every subscription active in the
+// filter period with the median price of the same service and plan tier, as
+// told apart by canonical.Product, and returns those deviating by more than
+// filter.Threshold (0.5 means 50%). Services with fewer than
+// filter.MinSamples subscriptions are skipped because their median says
+// little about the typical price.
+func (s *SubscriptionService) FindPriceAnomalies(ctx context.Context, filter model.AnomalyFilter) ([]model.PriceAnomaly, error) {
+	if err := access.CheckUnrestricted(ctx); err != nil {
+		return nil, err
+	}
+
+	if filter.Period.IsZero() {
+		now := time.Now().UTC()
+		filter.Period = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	if filter.Threshold <= 0 {
+		filter.Threshold = 0.5
+	}
+	if filter.MinSamples <= 0 {
+		filter.MinSamples = 5
+	}
+
+	subscriptions, err := s.subscriptionRepository.ListActiveSubscriptions(ctx, nil, filter.Period)
+	if err != nil {
+		return nil, fmt.Errorf("subscriptionRepository.ListActiveSubscriptions: %w", err)
+	}
+
+	byService := make(map[string][]model.Subscription)
+	var names []string
+	for _, subscription := range subscriptions {
+		name := canonical.Product(subscription.ServiceName)
+		if _, ok := byService[name]; !ok {
+			names = append(names, name)
+		}
+		byService[name] = append(byService[name], subscription)
+	}
+
+	var anomalies []model.PriceAnomaly
+	for _, name := range names {
+		group := byService[name]
+		if len(group) < filter.MinSamples {
+			continue
+		}
+
+		stats := priceStatistics(name, group)
+		for _, subscription := range group {
+			deviation := float64(subscription.Price-stats.Median) / float64(stats.Median)
+			if deviation > filter.Threshold || -deviation > filter.Threshold {
+				anomalies = append(anomalies, model.PriceAnomaly{
+					Subscription: subscription,
+					Statistics:   stats,
+					Deviation:    deviation,
+				})
+			}
+		}
+	}
+
+	return anomalies, nil
+}
+
+func priceStatistics(serviceName string, subscriptions []model.Subscription) model.PriceStatistics {
+	prices := make([]int, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		prices = append(prices, subscription.Price)
+	}
+	sort.Ints(prices)
+
+	median := prices[len(prices)/2]
+	if len(prices)%2 == 0 {
+		median = (prices[len(prices)/2-1] + prices[len(prices)/2]) / 2
+	}
+
+	return model.PriceStatistics{
+		ServiceName: serviceName,
+		Count:       len(prices),
+		Median:      median,
+		Min:         prices[0],
+		Max:         prices[len(prices)-1],
+	}
+}
+
+// SuggestSubscriptions detects recurring charges in the user's bank statement
+// and returns them as subscriptions ready to be created. Suggestions matching a
+// subscription the user already has are marked as already tracked.
+func (s *SubscriptionService) SuggestSubscriptions(ctx context.Context, userID uuid.UUID, transactions []model.Transaction) ([]model.SuggestedSubscription, error) {
+	if userID == uuid.Nil {
+		return nil, model.NewValidationError("user_id is required")
+	}
+	if err := access.CheckAccess(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	suggestions := detector.Detect(transactions)
+	if len(suggestions) == 0 {
+		return nil, nil
+	}
+
+	existing, err := s.subscriptionRepository.ListActiveSubscriptions(ctx, []uuid.UUID{userID}, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("subscriptionRepository.ListActiveSubscriptions: %w", err)
+	}
+
+	tracked := make(map[string]bool, len(existing))
+	for _, subscription := range existing {
+		tracked[canonical.Name(subscription.ServiceName)] = true
+	}
+
+	for i := range suggestions {
+		suggestions[i].Subscription.UserID = userID
+		suggestions[i].AlreadyTracked = tracked[canonical.Name(suggestions[i].Subscription.ServiceName)]
+	}
+
+	return suggestions, nil
+}
+
+// PublishUpcomingRenewals announces the renewals happening within the given
+// period with a renewal.upcoming event per subscription. Subscriptions renew
+// on the first day of every month they stay active; a subscription starting
+// on that day is new rather than renewed. The events have stable IDs and the
+// outbox ignores IDs it already has, so running this several times before a
+// renewal announces it once. It returns how many renewals were considered.
+func (s *SubscriptionService) PublishUpcomingRenewals(ctx context.Context, within time.Duration) (int, error) {
+	now := time.Now().UTC()
+	renewalDate := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, 1, 0)
+	if renewalDate.Sub(now) > within {
+		return 0, nil
+	}
+
+	subscriptions, err := s.subscriptionRepository.ListActiveSubscriptions(ctx, nil, renewalDate)
+	if err != nil {
+		return 0, fmt.Errorf("subscriptionRepository.ListActiveSubscriptions: %w", err)
+	}
+
+	announced := 0
+	for _, subscription := range subscriptions {
+		if !subscription.StartDate.Before(renewalDate) {
+			continue
+		}
+
+		event, err := newEvent(model.EventRenewalUpcoming, model.RenewalEventData{
+			Subscription: subscription,
+			RenewalDate:  renewalDate,
+		})
+		if err != nil {
+			return announced, err
+		}
+		event.ID = uuid.NewSHA1(renewalEventNamespace, []byte(subscription.ID.String()+"/"+renewalDate.Format(time.DateOnly)))
+
+		if err := s.eventRecorder.AddEvent(ctx, event); err != nil {
+			return announced, fmt.Errorf("eventRecorder.AddEvent: %w", err)
+		}
+		announced++
+	}
+
+	return announced, nil
+}
+
+// recordEvent writes an event about a subscription change to the outbox.
+// Called within the transaction of the change, so the event is published if
+// and only if the change is committed.
+func (s *SubscriptionService) recordEvent(ctx context.Context, eventType model.EventType, subscription model.Subscription) error {
+	event, err := newEvent(eventType, subscription)
+	if err != nil {
+		return err
+	}
+
+	if err := s.eventRecorder.AddEvent(ctx, event); err != nil {
+		return fmt.Errorf("eventRecorder.AddEvent: %w", err)
+	}
+	return nil
+}
+
+func newEvent(eventType model.EventType, data any) (model.Event, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return model.Event{}, fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	return model.Event{
+		ID:         uuid.New(),
+		Type:       eventType,
+		OccurredAt: time.Now().UTC(),
+		Payload:    payload,
+	}, nil
+}
+
+// evaluateBudgets checks the user's budgets after a change to their
+// subscriptions. The change itself is already stored, so a failure here is
+// only logged.
+func (s *SubscriptionService) evaluateBudgets(ctx context.Context, userID uuid.UUID) {
+	if err := s.budgetEvaluator.EvaluateBudgets(ctx, userID); err != nil {
+		s.logger.Error("failed to evaluate budgets", "user_id", userID, "error", err)
+	}
+}
+
+// checkBatchAccess applies the rules of the single routes to a batch
+// operation: only admins delete, and callers restricted to their own data
+// only write their own subscriptions.
+func checkBatchAccess(ctx context.Context, operation model.BatchOperation) error {
+	if operation.Type == model.BatchOperationDelete {
+		return access.CheckRole(ctx, model.RoleAdmin)
+	}
+	return access.CheckAccess(ctx, operation.Subscription.UserID)
+}
+
+// getOwned returns the stored subscription, or sql.ErrNoRows if it belongs to
+// another user than the one the caller is restricted to, as
+// GetSubscriptionByID does.
+func (s *SubscriptionService) getOwned(ctx context.Context, id uuid.UUID) (model.Subscription, error) {
+	subscription, err := s.subscriptionRepository.GetSubscriptionByID(ctx, id)
+	if err == nil && !access.CanAccess(ctx, subscription.UserID) {
+		err = sql.ErrNoRows
+	}
+	if err != nil {
+		return model.Subscription{}, fmt.Errorf("subscriptionRepository.GetSubscriptionByID: %w", err)
+	}
+	return subscription, nil
+}
+
+func validateSubscription(subscription model.Subscription) error {
+	if subscription.ServiceName == "" {
+		return model.NewValidationError("service_name is required")
+	}
+	if subscription.Price <= 0 {
+		return model.NewValidationError("price must be positive")
+	}
+	if subscription.UserID == uuid.Nil {
+		return model.NewValidationError("user_id is required")
+	}
+	if subscription.StartDate.IsZero() {
+		return model.NewValidationError("start_date is required")
+	}
+	return nil
+}
+
+var batchEventTypes = map[model.BatchOperationType]model.EventType{
+	model.BatchOperationCreate: model.EventSubscriptionCreated,
+	model.BatchOperationUpdate: model.EventSubscriptionUpdated,
+	model.BatchOperationDelete: model.EventSubscriptionDeleted,
+}
+
+func validateBatchOperation(operation model.BatchOperation) error {
+	switch operation.Type {
+	case model.BatchOperationCreate:
+		return validateSubscription(operation.Subscription)
+	case model.BatchOperationUpdate:
+		if operation.ID == uuid.Nil {
+			return model.NewValidationError("id is required")
+		}
+		return validateSubscription(operation.Subscription)
+	case model.BatchOperationDelete:
+		if operation.ID == uuid.Nil {
+			return model.NewValidationError("id is required")
+		}
+		return nil
+	default:
+		return model.NewValidationError("unknown operation %q", operation.Type)
+	}
+}
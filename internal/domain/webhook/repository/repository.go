@@ -0,0 +1,241 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/infra/database"
+	"github.com/golangtestcases/subscribe-service/internal/infra/tenant"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// PostgreSQLRepository scopes every query to the tenant of its context, so
+// that a tenant's events only reach the tenant's own webhooks.
+type PostgreSQLRepository struct {
+	db *sql.DB
+}
+
+func NewPostgreSQLRepository(db *sql.DB) *PostgreSQLRepository {
+	return &PostgreSQLRepository{db: db}
+}
+
+// conn returns the transaction carried by ctx, if any, or the connection pool.
+func (r *PostgreSQLRepository) conn(ctx context.Context) database.Querier {
+	return database.Conn(ctx, r.db)
+}
+
+func (r *PostgreSQLRepository) CreateWebhook(ctx context.Context, webhook model.Webhook) (model.Webhook, error) {
+	webhook.ID = uuid.New()
+	webhook.CreatedAt = time.Now()
+	webhook.UpdatedAt = time.Now()
+
+	query := `
+		INSERT INTO webhooks (id, tenant_id, url, secret, event_types, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := r.conn(ctx).ExecContext(ctx, query,
+		webhook.ID, tenant.ID(ctx), webhook.URL, webhook.Secret, eventTypesArray(webhook.EventTypes),
+		webhook.CreatedAt, webhook.UpdatedAt,
+	)
+
+	return webhook, err
+}
+
+func (r *PostgreSQLRepository) GetWebhookByID(ctx context.Context, id uuid.UUID) (model.Webhook, error) {
+	query := `
+		SELECT id, url, secret, event_types, created_at, updated_at
+		FROM webhooks WHERE tenant_id = $1 AND id = $2
+	`
+
+	return scanWebhook(r.conn(ctx).QueryRowContext(ctx, query, tenant.ID(ctx), id))
+}
+
+func (r *PostgreSQLRepository) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM webhooks WHERE tenant_id = $1 AND id = $2`
+	result, err := r.conn(ctx).ExecContext(ctx, query, tenant.ID(ctx), id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *PostgreSQLRepository) ListWebhooks(ctx context.Context) ([]model.Webhook, error) {
+	query := `
+		SELECT id, url, secret, event_types, created_at, updated_at
+		FROM webhooks WHERE tenant_id = $1 ORDER BY created_at
+	`
+
+	return r.queryWebhooks(ctx, query, tenant.ID(ctx))
+}
+
+// ListWebhooksForEvent returns the webhooks subscribed to the event type.
+func (r *PostgreSQLRepository) ListWebhooksForEvent(ctx context.Context, eventType model.EventType) ([]model.Webhook, error) {
+	query := `
+		SELECT id, url, secret, event_types, created_at, updated_at
+		FROM webhooks WHERE tenant_id = $1 AND $2 = ANY(event_types) ORDER BY created_at
+	`
+
+	return r.queryWebhooks(ctx, query, tenant.ID(ctx), string(eventType))
+}
+
+// CreateDelivery queues the delivery unless the event has already been queued
+// for the webhook, so publishing the same event twice is harmless.
+func (r *PostgreSQLRepository) CreateDelivery(ctx context.Context, delivery model.WebhookDelivery) error {
+	delivery.ID = uuid.New()
+	delivery.CreatedAt = time.Now()
+	delivery.UpdatedAt = time.Now()
+
+	query := `
+		INSERT INTO webhook_deliveries (id, tenant_id, webhook_id, event_id, event_type, payload, status, next_attempt_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (webhook_id, event_id) DO NOTHING
+	`
+
+	_, err := r.conn(ctx).ExecContext(ctx, query,
+		delivery.ID, tenant.ID(ctx), delivery.WebhookID, delivery.EventID, delivery.EventType,
+		[]byte(delivery.Payload), delivery.Status, delivery.NextAttemptAt,
+		delivery.CreatedAt, delivery.UpdatedAt,
+	)
+
+	return err
+}
+
+// ClaimDueDeliveries picks up to limit pending deliveries whose next attempt
+// is due and pushes their next attempt lease into the future, so that other
+// instances skip them while they are being sent. A delivery whose sender
+// crashes is retried once the lease expires.
+func (r *PostgreSQLRepository) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]model.WebhookDelivery, error) {
+	now := time.Now()
+
+	query := `
+		UPDATE webhook_deliveries SET next_attempt_at = $4
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE tenant_id = $1 AND status = 'pending' AND next_attempt_at <= $3
+			ORDER BY next_attempt_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, webhook_id, event_id, event_type, payload, status, attempts,
+			response_status, last_error, next_attempt_at, delivered_at, created_at, updated_at
+	`
+
+	return r.queryDeliveries(ctx, query, tenant.ID(ctx), limit, now, now.Add(lease))
+}
+
+func (r *PostgreSQLRepository) UpdateDelivery(ctx context.Context, delivery model.WebhookDelivery) error {
+	delivery.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $3, attempts = $4, response_status = $5, last_error = $6,
+			next_attempt_at = $7, delivered_at = $8, updated_at = $9
+		WHERE tenant_id = $1 AND id = $2
+	`
+
+	_, err := r.conn(ctx).ExecContext(ctx, query,
+		tenant.ID(ctx), delivery.ID, delivery.Status, delivery.Attempts, delivery.ResponseStatus,
+		delivery.LastError, delivery.NextAttemptAt, delivery.DeliveredAt, delivery.UpdatedAt,
+	)
+
+	return err
+}
+
+func (r *PostgreSQLRepository) ListDeliveries(ctx context.Context, webhookID uuid.UUID, limit, offset int) ([]model.WebhookDelivery, error) {
+	query := `
+		SELECT id, webhook_id, event_id, event_type, payload, status, attempts,
+			response_status, last_error, next_attempt_at, delivered_at, created_at, updated_at
+		FROM webhook_deliveries WHERE tenant_id = $1 AND webhook_id = $2
+		ORDER BY created_at DESC LIMIT $3 OFFSET $4
+	`
+
+	return r.queryDeliveries(ctx, query, tenant.ID(ctx), webhookID, limit, offset)
+}
+
+func (r *PostgreSQLRepository) queryWebhooks(ctx context.Context, query string, args ...any) ([]model.Webhook, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var webhooks []model.Webhook
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	return webhooks, rows.Err()
+}
+
+func (r *PostgreSQLRepository) queryDeliveries(ctx context.Context, query string, args ...any) ([]model.WebhookDelivery, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []model.WebhookDelivery
+	for rows.Next() {
+		var delivery model.WebhookDelivery
+		var payload []byte
+		err := rows.Scan(
+			&delivery.ID, &delivery.WebhookID, &delivery.EventID, &delivery.EventType,
+			&payload, &delivery.Status, &delivery.Attempts, &delivery.ResponseStatus,
+			&delivery.LastError, &delivery.NextAttemptAt, &delivery.DeliveredAt,
+			&delivery.CreatedAt, &delivery.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		delivery.Payload = payload
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanWebhook(row scanner) (model.Webhook, error) {
+	var webhook model.Webhook
+	var eventTypes pq.StringArray
+	err := row.Scan(
+		&webhook.ID, &webhook.URL, &webhook.Secret, &eventTypes,
+		&webhook.CreatedAt, &webhook.UpdatedAt,
+	)
+	if err != nil {
+		return model.Webhook{}, err
+	}
+
+	webhook.EventTypes = make([]model.EventType, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		webhook.EventTypes = append(webhook.EventTypes, model.EventType(eventType))
+	}
+	return webhook, nil
+}
+
+func eventTypesArray(eventTypes []model.EventType) pq.StringArray {
+	array := make(pq.StringArray, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		array = append(array, string(eventType))
+	}
+	return array
+}
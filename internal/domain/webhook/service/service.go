@@ -0,0 +1,305 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+const (
+	// claimBatchSize is how many due deliveries are sent per DeliverDue call.
+	// The claim lease grows with it, and so does the time a crashed instance
+	// holds deliveries back.
+	claimBatchSize = 20
+	// claimLeaseMargin is added to the time the sends of a batch may take,
+	// for the database writes between them.
+	claimLeaseMargin = time.Minute
+	// maxErrorLength bounds the error text kept in the delivery log.
+	maxErrorLength = 1000
+)
+
+type WebhookRepository interface {
+	CreateWebhook(context.Context, model.Webhook) (model.Webhook, error)
+	GetWebhookByID(context.Context, uuid.UUID) (model.Webhook, error)
+	DeleteWebhook(context.Context, uuid.UUID) error
+	ListWebhooks(context.Context) ([]model.Webhook, error)
+	ListWebhooksForEvent(context.Context, model.EventType) ([]model.Webhook, error)
+	CreateDelivery(context.Context, model.WebhookDelivery) error
+	ClaimDueDeliveries(context.Context, int, time.Duration) ([]model.WebhookDelivery, error)
+	UpdateDelivery(context.Context, model.WebhookDelivery) error
+	ListDeliveries(context.Context, uuid.UUID, int, int) ([]model.WebhookDelivery, error)
+}
+
+// Sender posts a delivery payload to the webhook URL and returns the HTTP
+// status of the response. A non-2xx status is reported as an error.
+type Sender interface {
+	Send(ctx context.Context, webhook model.Webhook, delivery model.WebhookDelivery) (int, error)
+}
+
+// RetryPolicy controls redelivery of failed webhooks: the n-th retry waits
+// InitialBackoff * 2^(n-1), capped at MaxBackoff, and a delivery is given up
+// after MaxAttempts attempts.
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+type WebhookService struct {
+	webhookRepository WebhookRepository
+	sender            Sender
+	retryPolicy       RetryPolicy
+	sendTimeout       time.Duration
+	logger            *slog.Logger
+}
+
+// NewWebhookService returns a service that gives up on a send after
+// sendTimeout.
+func NewWebhookService(webhookRepository WebhookRepository, sender Sender, retryPolicy RetryPolicy, sendTimeout time.Duration, logger *slog.Logger) *WebhookService {
+	return &WebhookService{
+		webhookRepository: webhookRepository,
+		sender:            sender,
+		retryPolicy:       retryPolicy,
+		sendTimeout:       sendTimeout,
+		logger:            logger,
+	}
+}
+
+// CreateWebhook registers the webhook. When no secret is given a random one is
+// generated; the returned webhook is the only place the caller can read it.
+func (s *WebhookService) CreateWebhook(ctx context.Context, webhook model.Webhook) (model.Webhook, error) {
+	if err := validateWebhook(webhook); err != nil {
+		return model.Webhook{}, err
+	}
+
+	if webhook.Secret == "" {
+		secret, err := generateSecret()
+		if err != nil {
+			return model.Webhook{}, fmt.Errorf("generateSecret: %w", err)
+		}
+		webhook.Secret = secret
+	}
+
+	newWebhook, err := s.webhookRepository.CreateWebhook(ctx, webhook)
+	if err != nil {
+		return model.Webhook{}, fmt.Errorf("webhookRepository.CreateWebhook: %w", err)
+	}
+
+	return newWebhook, nil
+}
+
+func (s *WebhookService) GetWebhookByID(ctx context.Context, id uuid.UUID) (model.Webhook, error) {
+	if id == uuid.Nil {
+		return model.Webhook{}, model.NewValidationError("id is required")
+	}
+
+	webhook, err := s.webhookRepository.GetWebhookByID(ctx, id)
+	if err != nil {
+		return model.Webhook{}, fmt.Errorf("webhookRepository.GetWebhookByID: %w", err)
+	}
+
+	return webhook, nil
+}
+
+func (s *WebhookService) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	if id == uuid.Nil {
+		return model.NewValidationError("id is required")
+	}
+
+	err := s.webhookRepository.DeleteWebhook(ctx, id)
+	if err != nil {
+		return fmt.Errorf("webhookRepository.DeleteWebhook: %w", err)
+	}
+
+	return nil
+}
+
+func (s *WebhookService) ListWebhooks(ctx context.Context) ([]model.Webhook, error) {
+	webhooks, err := s.webhookRepository.ListWebhooks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("webhookRepository.ListWebhooks: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+func (s *WebhookService) ListDeliveries(ctx context.Context, webhookID uuid.UUID, limit, offset int) ([]model.WebhookDelivery, error) {
+	if webhookID == uuid.Nil {
+		return nil, model.NewValidationError("id is required")
+	}
+
+	if _, err := s.webhookRepository.GetWebhookByID(ctx, webhookID); err != nil {
+		return nil, fmt.Errorf("webhookRepository.GetWebhookByID: %w", err)
+	}
+
+	deliveries, err := s.webhookRepository.ListDeliveries(ctx, webhookID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("webhookRepository.ListDeliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// Publish queues a delivery of the event to every webhook subscribed to its
+// type. The deliveries are sent by DeliverDue.
+func (s *WebhookService) Publish(ctx context.Context, event model.Event) error {
+	webhooks, err := s.webhookRepository.ListWebhooksForEvent(ctx, event.Type)
+	if err != nil {
+		return fmt.Errorf("webhookRepository.ListWebhooksForEvent: %w", err)
+	}
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	for _, webhook := range webhooks {
+		err := s.webhookRepository.CreateDelivery(ctx, model.WebhookDelivery{
+			WebhookID:     webhook.ID,
+			EventID:       event.ID,
+			EventType:     event.Type,
+			Payload:       payload,
+			Status:        model.DeliveryStatusPending,
+			NextAttemptAt: time.Now(),
+		})
+		if err != nil {
+			return fmt.Errorf("webhookRepository.CreateDelivery: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// DeliverDue sends the deliveries whose next attempt is due and records the
+// outcome of each. It returns how many deliveries were attempted.
+//
+// The deliveries are claimed for as long as sending all of them one after
+// another may take, so that other instances do not claim and send them again
+// while they are still waiting for their turn.
+func (s *WebhookService) DeliverDue(ctx context.Context) (int, error) {
+	lease := claimBatchSize*s.sendTimeout + claimLeaseMargin
+	deliveries, err := s.webhookRepository.ClaimDueDeliveries(ctx, claimBatchSize, lease)
+	if err != nil {
+		return 0, fmt.Errorf("webhookRepository.ClaimDueDeliveries: %w", err)
+	}
+
+	webhooks := make(map[uuid.UUID]model.Webhook)
+	for _, delivery := range deliveries {
+		webhook, ok := webhooks[delivery.WebhookID]
+		if !ok {
+			webhook, err = s.webhookRepository.GetWebhookByID(ctx, delivery.WebhookID)
+			if err != nil {
+				return 0, fmt.Errorf("webhookRepository.GetWebhookByID: %w", err)
+			}
+			webhooks[webhook.ID] = webhook
+		}
+
+		delivery = s.attempt(ctx, webhook, delivery)
+		if err := s.webhookRepository.UpdateDelivery(ctx, delivery); err != nil {
+			return 0, fmt.Errorf("webhookRepository.UpdateDelivery: %w", err)
+		}
+	}
+
+	return len(deliveries), nil
+}
+
+func (s *WebhookService) attempt(ctx context.Context, webhook model.Webhook, delivery model.WebhookDelivery) model.WebhookDelivery {
+	sendCtx, cancel := context.WithTimeout(ctx, s.sendTimeout)
+	statusCode, err := s.sender.Send(sendCtx, webhook, delivery)
+	cancel()
+
+	delivery.Attempts++
+	delivery.ResponseStatus = nil
+	if statusCode != 0 {
+		delivery.ResponseStatus = &statusCode
+	}
+
+	if err == nil {
+		now := time.Now()
+		delivery.Status = model.DeliveryStatusSucceeded
+		delivery.DeliveredAt = &now
+		delivery.LastError = nil
+		return delivery
+	}
+
+	message := err.Error()
+	if len(message) > maxErrorLength {
+		message = message[:maxErrorLength]
+	}
+	delivery.LastError = &message
+
+	if delivery.Attempts >= s.retryPolicy.MaxAttempts {
+		delivery.Status = model.DeliveryStatusFailed
+		s.logger.Warn("webhook delivery failed",
+			"webhook_id", webhook.ID, "delivery_id", delivery.ID,
+			"attempts", delivery.Attempts, "error", err,
+		)
+		return delivery
+	}
+
+	delivery.NextAttemptAt = time.Now().Add(s.backoff(delivery.Attempts))
+	return delivery
+}
+
+// backoff returns the delay before the retry that follows the given number of
+// attempts.
+func (s *WebhookService) backoff(attempts int) time.Duration {
+	delay := s.retryPolicy.InitialBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= s.retryPolicy.MaxBackoff {
+			return s.retryPolicy.MaxBackoff
+		}
+	}
+	return delay
+}
+
+func validateWebhook(webhook model.Webhook) error {
+	if webhook.URL == "" {
+		return model.NewValidationError("url is required")
+	}
+	u, err := url.Parse(webhook.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return model.NewValidationError("url must be an absolute http or https URL")
+	}
+	// Host names are resolved again and checked by the sender on every
+	// delivery; here only the ones that are never public are rejected.
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return model.NewValidationError("url must point to a public address")
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && !model.IsPublicAddr(addr) {
+		return model.NewValidationError("url must point to a public address")
+	}
+	if len(webhook.EventTypes) == 0 {
+		return model.NewValidationError("event_types is required")
+	}
+	for _, eventType := range webhook.EventTypes {
+		if !slices.Contains(model.EventTypes, eventType) {
+			return model.NewValidationError("unknown event type %q", eventType)
+		}
+	}
+	return nil
+}
+
+func generateSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
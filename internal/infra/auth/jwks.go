@@ -0,0 +1,139 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+// HMACKey returns a key verifying HS256 tokens signed with secret.
+func HMACKey(secret string) JWTKey {
+	return JWTKey{Algorithm: AlgorithmHS256, Key: []byte(secret)}
+}
+
+// ReadRSAPublicKeyFile returns a key verifying RS256 tokens with the public
+// key in the PEM file, either a PKIX "PUBLIC KEY" or a PKCS #1
+// "RSA PUBLIC KEY" block.
+func ReadRSAPublicKeyFile(path string) (JWTKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return JWTKey{}, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return JWTKey{}, fmt.Errorf("%s: no PEM block found", path)
+	}
+
+	var publicKey *rsa.PublicKey
+	switch block.Type {
+	case "PUBLIC KEY":
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return JWTKey{}, fmt.Errorf("%s: %w", path, err)
+		}
+		var ok bool
+		if publicKey, ok = key.(*rsa.PublicKey); !ok {
+			return JWTKey{}, fmt.Errorf("%s: not an RSA public key", path)
+		}
+	case "RSA PUBLIC KEY":
+		if publicKey, err = x509.ParsePKCS1PublicKey(block.Bytes); err != nil {
+			return JWTKey{}, fmt.Errorf("%s: %w", path, err)
+		}
+	default:
+		return JWTKey{}, fmt.Errorf("%s: unexpected PEM block %q", path, block.Type)
+	}
+
+	return JWTKey{Algorithm: AlgorithmRS256, Key: publicKey}, nil
+}
+
+type jwkSet struct {
+	Keys []jwk `json:"keys"`
+}
+
+// jwk is the part of a JSON Web Key (RFC 7517) needed for RSA and symmetric
+// keys.
+type jwk struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	N         string `json:"n"`
+	E         string `json:"e"`
+	K         string `json:"k"`
+}
+
+// ReadJWKSFile returns the keys of a JSON Web Key Set file. RSA keys verify
+// RS256 tokens and symmetric ("oct") keys HS256 tokens. Keys meant for
+// encryption or for other algorithms are skipped.
+func ReadJWKSFile(path string) ([]JWTKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var set jwkSet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	var keys []JWTKey
+	for i, key := range set.Keys {
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+
+		var jwtKey JWTKey
+		switch key.KeyType {
+		case "RSA":
+			jwtKey, err = rsaJWK(key)
+		case "oct":
+			jwtKey, err = octJWK(key)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: key %d: %w", path, i, err)
+		}
+		if key.Algorithm != "" && key.Algorithm != jwtKey.Algorithm {
+			continue
+		}
+		keys = append(keys, jwtKey)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%s: no RS256 or HS256 signing keys", path)
+	}
+
+	return keys, nil
+}
+
+func rsaJWK(key jwk) (JWTKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(key.N)
+	if err != nil || len(n) == 0 {
+		return JWTKey{}, errors.New("invalid modulus")
+	}
+	e, err := base64.RawURLEncoding.DecodeString(key.E)
+	if err != nil || len(e) == 0 || len(e) > 4 {
+		return JWTKey{}, errors.New("invalid exponent")
+	}
+
+	publicKey := &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(new(big.Int).SetBytes(e).Int64()),
+	}
+	return JWTKey{ID: key.KeyID, Algorithm: AlgorithmRS256, Key: publicKey}, nil
+}
+
+func octJWK(key jwk) (JWTKey, error) {
+	secret, err := base64.RawURLEncoding.DecodeString(key.K)
+	if err != nil || len(secret) == 0 {
+		return JWTKey{}, errors.New("invalid key value")
+	}
+	return JWTKey{ID: key.KeyID, Algorithm: AlgorithmHS256, Key: secret}, nil
+}
@@ -0,0 +1,241 @@
+// Package auth verifies the JWTs that identify end users. The identities it
+// returns are carried in a context by the access package.
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/access"
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+// Algorithms accepted in the alg header of a JWT. Everything else, "none"
+// included, is rejected.
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+)
+
+// jwtLeeway tolerates clocks of the issuer and the service drifting apart
+// when checking exp and nbf.
+const jwtLeeway = time.Minute
+
+// DefaultJWTRole is granted to tokens without a roles claim, so that end
+// users can manage their own subscriptions.
+const DefaultJWTRole = model.RoleEditor
+
+// JWTKey verifies the signatures of tokens. Key is a []byte secret for HS256
+// and an *rsa.PublicKey for RS256. A key with an ID only verifies tokens with
+// the same kid header; a key without one verifies any token of its algorithm.
+type JWTKey struct {
+	ID        string
+	Algorithm string
+	Key       any
+}
+
+// JWTVerifier authenticates end users by JWTs issued by another service. The
+// sub claim must be the user ID, which restricts the caller to the data of
+// that user; the optional roles claim is an array of role names. The
+// tenant_id claim binds the token to a tenant: a token is never valid in every
+// tenant.
+type JWTVerifier struct {
+	keys          []JWTKey
+	issuer        string
+	audience      string
+	defaultTenant string
+	now           func() time.Time
+}
+
+// NewJWTVerifier returns a verifier accepting tokens signed with one of the
+// keys. If issuer or audience is not empty, the iss or aud claim must match
+// it. Tokens without a tenant_id claim are bound to defaultTenant, or
+// rejected if it is empty.
+func NewJWTVerifier(keys []JWTKey, issuer, audience, defaultTenant string) *JWTVerifier {
+	return &JWTVerifier{
+		keys:          keys,
+		issuer:        issuer,
+		audience:      audience,
+		defaultTenant: defaultTenant,
+		now:           time.Now,
+	}
+}
+
+type jwtHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+}
+
+type jwtClaims struct {
+	Subject   string      `json:"sub"`
+	Issuer    string      `json:"iss"`
+	Audience  jwtAudience `json:"aud"`
+	ExpiresAt *float64    `json:"exp"`
+	NotBefore *float64    `json:"nbf"`
+	Roles     *[]string   `json:"roles"`
+	TenantID  string      `json:"tenant_id"`
+}
+
+// jwtAudience is the aud claim, which is either one string or an array.
+type jwtAudience []string
+
+func (a *jwtAudience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = jwtAudience{single}
+		return nil
+	}
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return errors.New("aud must be a string or an array of strings")
+	}
+	*a = multiple
+	return nil
+}
+
+// IsJWT reports whether token looks like a compact JWT rather than an API
+// key, which never contains dots.
+func IsJWT(token string) bool {
+	return strings.Count(token, ".") == 2
+}
+
+// Authenticate returns the identity of the user the token was issued to. A
+// token that is malformed, badly signed, expired or meant for someone else is
+// reported as access.ErrInvalidCredentials with the reason.
+func (v *JWTVerifier) Authenticate(_ context.Context, token string) (model.Identity, error) {
+	claims, err := v.verify(token)
+	if err != nil {
+		return model.Identity{}, fmt.Errorf("%w: %s", access.ErrInvalidCredentials, err)
+	}
+
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil || userID == uuid.Nil {
+		return model.Identity{}, fmt.Errorf("%w: sub must be a user ID", access.ErrInvalidCredentials)
+	}
+
+	tenantID := claims.TenantID
+	if tenantID == "" {
+		if v.defaultTenant == "" {
+			return model.Identity{}, fmt.Errorf("%w: tenant_id is required", access.ErrInvalidCredentials)
+		}
+		tenantID = v.defaultTenant
+	}
+
+	roles := []model.Role{DefaultJWTRole}
+	if claims.Roles != nil {
+		roles = nil
+		for _, role := range *claims.Roles {
+			if slices.Contains(model.Roles, model.Role(role)) {
+				roles = append(roles, model.Role(role))
+			}
+		}
+	}
+
+	return model.Identity{
+		Subject:  "user:" + userID.String(),
+		Roles:    roles,
+		UserID:   userID,
+		TenantID: tenantID,
+	}, nil
+}
+
+func (v *JWTVerifier) verify(token string) (jwtClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return jwtClaims{}, errors.New("malformed token")
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return jwtClaims{}, fmt.Errorf("malformed header: %w", err)
+	}
+	if header.Algorithm != AlgorithmHS256 && header.Algorithm != AlgorithmRS256 {
+		return jwtClaims{}, fmt.Errorf("unsupported algorithm %q", header.Algorithm)
+	}
+
+	// Strict decoding rejects the variants of a signature that differ in
+	// the unused bits of the last character.
+	signature, err := base64.RawURLEncoding.Strict().DecodeString(parts[2])
+	if err != nil {
+		return jwtClaims{}, errors.New("malformed signature")
+	}
+	if !v.verifySignature(header, parts[0]+"."+parts[1], signature) {
+		return jwtClaims{}, errors.New("signature is not valid")
+	}
+
+	var claims jwtClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return jwtClaims{}, fmt.Errorf("malformed claims: %w", err)
+	}
+
+	now := v.now()
+	if claims.ExpiresAt == nil {
+		return jwtClaims{}, errors.New("exp is required")
+	}
+	if now.Add(-jwtLeeway).After(numericDate(*claims.ExpiresAt)) {
+		return jwtClaims{}, errors.New("token has expired")
+	}
+	if claims.NotBefore != nil && now.Add(jwtLeeway).Before(numericDate(*claims.NotBefore)) {
+		return jwtClaims{}, errors.New("token is not valid yet")
+	}
+	if v.issuer != "" && claims.Issuer != v.issuer {
+		return jwtClaims{}, errors.New("unexpected issuer")
+	}
+	if v.audience != "" && !slices.Contains(claims.Audience, v.audience) {
+		return jwtClaims{}, errors.New("unexpected audience")
+	}
+
+	return claims, nil
+}
+
+// verifySignature tries every key of the token's algorithm and kid, so that
+// keys can be rotated by configuring the old and the new one for a while.
+func (v *JWTVerifier) verifySignature(header jwtHeader, signed string, signature []byte) bool {
+	for _, key := range v.keys {
+		if key.Algorithm != header.Algorithm || (key.ID != "" && key.ID != header.KeyID) {
+			continue
+		}
+
+		switch k := key.Key.(type) {
+		case []byte:
+			mac := hmac.New(sha256.New, k)
+			mac.Write([]byte(signed))
+			if hmac.Equal(mac.Sum(nil), signature) {
+				return true
+			}
+		case *rsa.PublicKey:
+			digest := sha256.Sum256([]byte(signed))
+			if rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], signature) == nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// numericDate converts a JWT NumericDate, seconds since the epoch that may
+// have a fraction, to a time.
+func numericDate(seconds float64) time.Time {
+	whole, fraction := math.Modf(seconds)
+	return time.Unix(int64(whole), int64(fraction*1e9))
+}
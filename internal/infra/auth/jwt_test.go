@@ -0,0 +1,248 @@
+package auth_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/access"
+	"github.com/golangtestcases/subscribe-service/internal/infra/auth"
+	"github.com/google/uuid"
+)
+
+const testSecret = "secretsecretsecretsecretsecret12"
+
+var testUserID = uuid.MustParse("11111111-1111-1111-1111-111111111111")
+
+// testKeys holds an RSA key whose public half is in a PEM file and in a JWKS
+// file with the kid "rsa-1".
+type testKeys struct {
+	private   *rsa.PrivateKey
+	publicPEM []byte
+	pemFile   string
+	jwksFile  string
+}
+
+func newTestKeys(t *testing.T) testKeys {
+	t.Helper()
+
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&private.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	dir := t.TempDir()
+	pemFile := filepath.Join(dir, "public.pem")
+	if err := os.WriteFile(pemFile, publicPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	jwks, err := json.Marshal(map[string]any{"keys": []map[string]string{{
+		"kty": "RSA",
+		"kid": "rsa-1",
+		"use": "sig",
+		"alg": auth.AlgorithmRS256,
+		"n":   base64.RawURLEncoding.EncodeToString(private.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(private.E)).Bytes()),
+	}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwksFile := filepath.Join(dir, "jwks.json")
+	if err := os.WriteFile(jwksFile, jwks, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return testKeys{private: private, publicPEM: publicPEM, pemFile: pemFile, jwksFile: jwksFile}
+}
+
+// sign returns a compact JWT with the header and claims, signed by alg
+// whatever the header says: HS256 with secret, RS256 with the private key and
+// any other algorithm not at all.
+func (k testKeys) sign(t *testing.T, header, claims map[string]any, alg string, secret []byte) string {
+	t.Helper()
+
+	encode := func(v any) string {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	signed := encode(header) + "." + encode(claims)
+
+	var signature []byte
+	switch alg {
+	case auth.AlgorithmHS256:
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(signed))
+		signature = mac.Sum(nil)
+	case auth.AlgorithmRS256:
+		digest := sha256.Sum256([]byte(signed))
+		var err error
+		if signature, err = rsa.SignPKCS1v15(rand.Reader, k.private, crypto.SHA256, digest[:]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestJWTVerifier(t *testing.T) {
+	keys := newTestKeys(t)
+	pemKey, err := auth.ReadRSAPublicKeyFile(keys.pemFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwksKeys, err := auth.ReadJWKSFile(keys.jwksFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	claims := func(changes map[string]any) map[string]any {
+		c := map[string]any{
+			"sub":       testUserID.String(),
+			"exp":       now.Add(time.Hour).Unix(),
+			"tenant_id": "acme",
+		}
+		for name, value := range changes {
+			if value == nil {
+				delete(c, name)
+			} else {
+				c[name] = value
+			}
+		}
+		return c
+	}
+	hs256 := map[string]any{"alg": auth.AlgorithmHS256, "typ": "JWT"}
+	rs256 := map[string]any{"alg": auth.AlgorithmRS256, "typ": "JWT"}
+	rs256Kid := func(kid string) map[string]any {
+		return map[string]any{"alg": auth.AlgorithmRS256, "typ": "JWT", "kid": kid}
+	}
+
+	tests := []struct {
+		name          string
+		keys          []auth.JWTKey
+		defaultTenant string
+		token         string
+		wantTenant    string
+		wantErr       bool
+	}{
+		{
+			name:       "HS256",
+			keys:       []auth.JWTKey{auth.HMACKey(testSecret)},
+			token:      keys.sign(t, hs256, claims(nil), auth.AlgorithmHS256, []byte(testSecret)),
+			wantTenant: "acme",
+		},
+		{
+			name:       "RS256 from a PEM file",
+			keys:       []auth.JWTKey{pemKey},
+			token:      keys.sign(t, rs256, claims(nil), auth.AlgorithmRS256, nil),
+			wantTenant: "acme",
+		},
+		{
+			name:       "RS256 with a known kid",
+			keys:       jwksKeys,
+			token:      keys.sign(t, rs256Kid("rsa-1"), claims(nil), auth.AlgorithmRS256, nil),
+			wantTenant: "acme",
+		},
+		{
+			name:    "alg none",
+			keys:    []auth.JWTKey{auth.HMACKey(testSecret), pemKey},
+			token:   keys.sign(t, map[string]any{"alg": "none", "typ": "JWT"}, claims(nil), "none", nil),
+			wantErr: true,
+		},
+		{
+			name:    "HS256 signed with the RSA public key",
+			keys:    []auth.JWTKey{pemKey},
+			token:   keys.sign(t, hs256, claims(nil), auth.AlgorithmHS256, keys.publicPEM),
+			wantErr: true,
+		},
+		{
+			name:    "RS256 header over an HMAC signature",
+			keys:    []auth.JWTKey{auth.HMACKey(testSecret), pemKey},
+			token:   keys.sign(t, rs256, claims(nil), auth.AlgorithmHS256, []byte(testSecret)),
+			wantErr: true,
+		},
+		{
+			name:    "wrong secret",
+			keys:    []auth.JWTKey{auth.HMACKey(testSecret)},
+			token:   keys.sign(t, hs256, claims(nil), auth.AlgorithmHS256, []byte("another-secret-another-secret-12")),
+			wantErr: true,
+		},
+		{
+			name:    "missing exp",
+			keys:    []auth.JWTKey{auth.HMACKey(testSecret)},
+			token:   keys.sign(t, hs256, claims(map[string]any{"exp": nil}), auth.AlgorithmHS256, []byte(testSecret)),
+			wantErr: true,
+		},
+		{
+			name:    "expired exp",
+			keys:    []auth.JWTKey{auth.HMACKey(testSecret)},
+			token:   keys.sign(t, hs256, claims(map[string]any{"exp": now.Add(-time.Hour).Unix()}), auth.AlgorithmHS256, []byte(testSecret)),
+			wantErr: true,
+		},
+		{
+			name:    "unknown kid",
+			keys:    jwksKeys,
+			token:   keys.sign(t, rs256Kid("rsa-2"), claims(nil), auth.AlgorithmRS256, nil),
+			wantErr: true,
+		},
+		{
+			name:    "sub that is not a user ID",
+			keys:    []auth.JWTKey{auth.HMACKey(testSecret)},
+			token:   keys.sign(t, hs256, claims(map[string]any{"sub": "alice"}), auth.AlgorithmHS256, []byte(testSecret)),
+			wantErr: true,
+		},
+		{
+			name:          "missing tenant_id with a default tenant",
+			keys:          []auth.JWTKey{auth.HMACKey(testSecret)},
+			defaultTenant: "default",
+			token:         keys.sign(t, hs256, claims(map[string]any{"tenant_id": nil}), auth.AlgorithmHS256, []byte(testSecret)),
+			wantTenant:    "default",
+		},
+		{
+			name:    "missing tenant_id without a default tenant",
+			keys:    []auth.JWTKey{auth.HMACKey(testSecret)},
+			token:   keys.sign(t, hs256, claims(map[string]any{"tenant_id": nil}), auth.AlgorithmHS256, []byte(testSecret)),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier := auth.NewJWTVerifier(tt.keys, "", "", tt.defaultTenant)
+			identity, err := verifier.Authenticate(context.Background(), tt.token)
+			if tt.wantErr {
+				if !errors.Is(err, access.ErrInvalidCredentials) {
+					t.Fatalf("got identity %+v and error %v, want access.ErrInvalidCredentials", identity, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if identity.UserID != testUserID || identity.TenantID != tt.wantTenant {
+				t.Fatalf("got user %s in tenant %q, want %s in %q", identity.UserID, identity.TenantID, testUserID, tt.wantTenant)
+			}
+		})
+	}
+}
@@ -1,21 +1,43 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/golangtestcases/subscribe-service/internal/infra/tenant"
 	"github.com/joho/godotenv"
 )
 
+const (
+	StoragePostgres = "postgres"
+	StorageMemory   = "memory"
+	StorageSQLite   = "sqlite"
+)
+
 type Config struct {
+	Storage  string
 	Server   ServerConfig
 	Database DatabaseConfig
+	SQLite   SQLiteConfig
 	Logger   LoggerConfig
+	Jobs     JobsConfig
+	Webhooks WebhooksConfig
+	Outbox   OutboxConfig
+	Tracing  TracingConfig
+	Auth     AuthConfig
+	Tenants  TenantsConfig
 }
 
 type ServerConfig struct {
-	Host string
-	Port string
+	Host             string
+	Port             string
+	ShutdownDelay    time.Duration
+	ShutdownTimeout  time.Duration
+	ReadinessTimeout time.Duration
 }
 
 type DatabaseConfig struct {
@@ -25,12 +47,100 @@ type DatabaseConfig struct {
 	Password string
 	DBName   string
 	SSLMode  string
+	Replica  ReplicaConfig
+}
+
+// ReplicaConfig describes an optional read replica that serves reports.
+// Connection settings left empty are taken from the primary. The replica is
+// disabled when Host is empty.
+type ReplicaConfig struct {
+	Host          string
+	Port          string
+	User          string
+	Password      string
+	DBName        string
+	SSLMode       string
+	MaxLag        time.Duration
+	CheckInterval time.Duration
+}
+
+type SQLiteConfig struct {
+	Path string
 }
 
 type LoggerConfig struct {
 	Level string
 }
 
+type JobsConfig struct {
+	DuplicateDetectionInterval time.Duration
+	RenewalCheckInterval       time.Duration
+	RenewalNoticePeriod        time.Duration
+}
+
+type WebhooksConfig struct {
+	DeliveryInterval time.Duration
+	Timeout          time.Duration
+	MaxAttempts      int
+	InitialBackoff   time.Duration
+	MaxBackoff       time.Duration
+}
+
+// OutboxConfig selects where events from the outbox go besides the in-process
+// bus, which always receives them.
+type OutboxConfig struct {
+	DispatchInterval time.Duration
+	Sinks            []string
+	HTTPURL          string
+	HTTPTimeout      time.Duration
+}
+
+// TracingConfig selects where spans go. Tracing is off with an empty
+// Exporter; "stdout" and "file" write OTLP/JSON lines.
+type TracingConfig struct {
+	Exporter    string
+	File        string
+	ServiceName string
+}
+
+// AuthConfig controls API authentication. AdminKey is accepted as a key with
+// the admin role besides the issued keys, to issue the first ones; it is
+// required when Enabled.
+type AuthConfig struct {
+	Enabled  bool
+	AdminKey string
+	JWT      JWTConfig
+}
+
+// JWTConfig lists the keys verifying the tokens of end users: an HS256
+// Secret, an RS256 PublicKeyFile in PEM and a JWKSFile, in any combination.
+// JWTs are rejected when none is set. Issuer and Audience, if set, must match
+// the iss and aud claims.
+type JWTConfig struct {
+	Secret        string
+	PublicKeyFile string
+	JWKSFile      string
+	Issuer        string
+	Audience      string
+}
+
+// TenantsConfig lists the tenants in File, a JSON file read by tenant.ReadFile.
+// Without one the service runs for the single tenant "default". Requests name
+// their tenant in the Header, unless their credential belongs to one.
+// DefaultCurrency applies to tenants that do not set their own.
+type TenantsConfig struct {
+	File            string
+	Header          string
+	DefaultCurrency string
+}
+
+const (
+	// minAdminKeyLength keeps the admin key as hard to guess as an issued key.
+	minAdminKeyLength = 32
+	// minJWTSecretLength is the key size RFC 7518 requires for HS256.
+	minJWTSecretLength = 32
+)
+
 func LoadConfig(configPath string) (*Config, error) {
 	if configPath != "" {
 		if err := godotenv.Load(configPath); err != nil {
@@ -39,6 +149,7 @@ func LoadConfig(configPath string) (*Config, error) {
 	}
 
 	config := &Config{
+		Storage: getEnv("STORAGE", StoragePostgres),
 		Server: ServerConfig{
 			Host: getEnv("SERVER_HOST", "localhost"),
 			Port: getEnv("SERVER_PORT", "8080"),
@@ -51,22 +162,236 @@ func LoadConfig(configPath string) (*Config, error) {
 			DBName:   getEnv("DB_NAME", "subscriptions"),
 			SSLMode:  getEnv("DB_SSLMODE", "disable"),
 		},
+		SQLite: SQLiteConfig{
+			Path: getEnv("SQLITE_PATH", "subscriptions.db"),
+		},
 		Logger: LoggerConfig{
 			Level: getEnv("LOG_LEVEL", "info"),
 		},
 	}
 
+	switch config.Storage {
+	case StoragePostgres, StorageMemory, StorageSQLite:
+	default:
+		return nil, fmt.Errorf("invalid STORAGE %q: must be %s, %s or %s", config.Storage, StoragePostgres, StorageSQLite, StorageMemory)
+	}
+
+	primary := config.Database
+	config.Database.Replica = ReplicaConfig{
+		Host:     os.Getenv("DB_REPLICA_HOST"),
+		Port:     getEnv("DB_REPLICA_PORT", primary.Port),
+		User:     getEnv("DB_REPLICA_USER", primary.User),
+		Password: getEnv("DB_REPLICA_PASSWORD", primary.Password),
+		DBName:   getEnv("DB_REPLICA_NAME", primary.DBName),
+		SSLMode:  getEnv("DB_REPLICA_SSLMODE", primary.SSLMode),
+	}
+
+	duplicateDetectionInterval, err := getEnvDuration("DUPLICATE_DETECTION_INTERVAL", 24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	config.Jobs.DuplicateDetectionInterval = duplicateDetectionInterval
+
+	if config.Server.ShutdownDelay, err = getEnvNonNegativeDuration("SERVER_SHUTDOWN_DELAY", 5*time.Second); err != nil {
+		return nil, err
+	}
+	if config.Server.ShutdownTimeout, err = getEnvDuration("SERVER_SHUTDOWN_TIMEOUT", 20*time.Second); err != nil {
+		return nil, err
+	}
+	if config.Server.ReadinessTimeout, err = getEnvDuration("READINESS_CHECK_TIMEOUT", 2*time.Second); err != nil {
+		return nil, err
+	}
+
+	if config.Jobs.RenewalCheckInterval, err = getEnvDuration("RENEWAL_CHECK_INTERVAL", time.Hour); err != nil {
+		return nil, err
+	}
+	if config.Jobs.RenewalNoticePeriod, err = getEnvDuration("RENEWAL_NOTICE_PERIOD", 72*time.Hour); err != nil {
+		return nil, err
+	}
+
+	if config.Database.Replica.MaxLag, err = getEnvDuration("DB_REPLICA_MAX_LAG", 10*time.Second); err != nil {
+		return nil, err
+	}
+	if config.Database.Replica.CheckInterval, err = getEnvDuration("DB_REPLICA_CHECK_INTERVAL", 5*time.Second); err != nil {
+		return nil, err
+	}
+
+	if config.Webhooks.DeliveryInterval, err = getEnvDuration("WEBHOOK_DELIVERY_INTERVAL", 5*time.Second); err != nil {
+		return nil, err
+	}
+	if config.Webhooks.Timeout, err = getEnvDuration("WEBHOOK_TIMEOUT", 10*time.Second); err != nil {
+		return nil, err
+	}
+	if config.Webhooks.MaxAttempts, err = getEnvInt("WEBHOOK_MAX_ATTEMPTS", 8); err != nil {
+		return nil, err
+	}
+	if config.Webhooks.InitialBackoff, err = getEnvDuration("WEBHOOK_INITIAL_BACKOFF", 30*time.Second); err != nil {
+		return nil, err
+	}
+	if config.Webhooks.MaxBackoff, err = getEnvDuration("WEBHOOK_MAX_BACKOFF", 6*time.Hour); err != nil {
+		return nil, err
+	}
+
+	if config.Outbox.DispatchInterval, err = getEnvDuration("OUTBOX_DISPATCH_INTERVAL", time.Second); err != nil {
+		return nil, err
+	}
+	if config.Outbox.HTTPTimeout, err = getEnvDuration("OUTBOX_HTTP_TIMEOUT", 10*time.Second); err != nil {
+		return nil, err
+	}
+	config.Outbox.HTTPURL = getEnv("OUTBOX_HTTP_URL", "")
+	for _, sink := range strings.Split(getEnv("OUTBOX_SINKS", ""), ",") {
+		sink = strings.TrimSpace(sink)
+		switch sink {
+		case "":
+			continue
+		case "log", "http":
+		default:
+			return nil, fmt.Errorf("invalid OUTBOX_SINKS: unknown sink %q", sink)
+		}
+		if sink == "http" && config.Outbox.HTTPURL == "" {
+			return nil, fmt.Errorf("OUTBOX_HTTP_URL is required for the http sink")
+		}
+		config.Outbox.Sinks = append(config.Outbox.Sinks, sink)
+	}
+
+	config.Tracing = TracingConfig{
+		Exporter:    getEnv("TRACING_EXPORTER", ""),
+		File:        getEnv("TRACING_FILE", "traces.jsonl"),
+		ServiceName: getEnv("TRACING_SERVICE_NAME", "subscribe-service"),
+	}
+	switch config.Tracing.Exporter {
+	case "", "stdout", "file":
+	default:
+		return nil, fmt.Errorf("invalid TRACING_EXPORTER %q: must be stdout or file", config.Tracing.Exporter)
+	}
+
+	if config.Auth.Enabled, err = getEnvBool("AUTH_ENABLED", true); err != nil {
+		return nil, err
+	}
+	config.Auth.AdminKey = os.Getenv("AUTH_ADMIN_KEY")
+	if config.Auth.AdminKey != "" && len(config.Auth.AdminKey) < minAdminKeyLength {
+		return nil, fmt.Errorf("invalid AUTH_ADMIN_KEY: must be at least %d characters", minAdminKeyLength)
+	}
+	// The admin key issues the first API keys. Without it a fresh database
+	// has no keys, and nobody could ever call the API.
+	if config.Auth.Enabled && config.Auth.AdminKey == "" {
+		return nil, errors.New("AUTH_ADMIN_KEY is required to issue the first API keys unless AUTH_ENABLED=false")
+	}
+
+	config.Auth.JWT = JWTConfig{
+		Secret:        os.Getenv("AUTH_JWT_SECRET"),
+		PublicKeyFile: getEnv("AUTH_JWT_PUBLIC_KEY_FILE", ""),
+		JWKSFile:      getEnv("AUTH_JWT_JWKS_FILE", ""),
+		Issuer:        getEnv("AUTH_JWT_ISSUER", ""),
+		Audience:      getEnv("AUTH_JWT_AUDIENCE", ""),
+	}
+	if config.Auth.JWT.Secret != "" && len(config.Auth.JWT.Secret) < minJWTSecretLength {
+		return nil, fmt.Errorf("invalid AUTH_JWT_SECRET: must be at least %d characters", minJWTSecretLength)
+	}
+
+	config.Tenants = TenantsConfig{
+		File:            getEnv("TENANTS_FILE", ""),
+		Header:          getEnv("TENANT_HEADER", "X-Tenant-ID"),
+		DefaultCurrency: getEnv("DEFAULT_CURRENCY", "RUB"),
+	}
+	if !tenant.ValidCurrency(config.Tenants.DefaultCurrency) {
+		return nil, fmt.Errorf("invalid DEFAULT_CURRENCY %q: must be an ISO 4217 code such as RUB", config.Tenants.DefaultCurrency)
+	}
+
 	return config, nil
 }
 
+// Enabled reports whether any key to verify JWTs is configured.
+func (c *JWTConfig) Enabled() bool {
+	return c.Secret != "" || c.PublicKeyFile != "" || c.JWKSFile != ""
+}
+
 func (c *DatabaseConfig) DSN() string {
 	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
 		c.Host, c.Port, c.User, c.Password, c.DBName, c.SSLMode)
 }
 
+func (c *ReplicaConfig) Enabled() bool {
+	return c.Host != ""
+}
+
+func (c *ReplicaConfig) DSN() string {
+	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		c.Host, c.Port, c.User, c.Password, c.DBName, c.SSLMode)
+}
+
+// DSN enables foreign keys for ON DELETE CASCADE and lets writers wait for
+// each other instead of failing with SQLITE_BUSY.
+func (c *SQLiteConfig) DSN() string {
+	return "file:" + c.Path + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_txlock=immediate"
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
 	}
 	return defaultValue
 }
+
+func getEnvDuration(key string, defaultValue time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	if duration <= 0 {
+		return 0, fmt.Errorf("invalid %s: must be positive", key)
+	}
+	return duration, nil
+}
+
+// getEnvNonNegativeDuration is like getEnvDuration but also accepts zero, for
+// settings where zero turns the feature off.
+func getEnvNonNegativeDuration(key string, defaultValue time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	if duration < 0 {
+		return 0, fmt.Errorf("invalid %s: must not be negative", key)
+	}
+	return duration, nil
+}
+
+func getEnvBool(key string, defaultValue bool) (bool, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return b, nil
+}
+
+func getEnvInt(key string, defaultValue int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	if n <= 0 {
+		return 0, fmt.Errorf("invalid %s: must be positive", key)
+	}
+	return n, nil
+}
@@ -0,0 +1,30 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// CheckMigrations verifies that the schema is at the given version of
+// golang-migrate and that no migration was left half-applied.
+func CheckMigrations(ctx context.Context, db *sql.DB, version uint) error {
+	var current uint
+	var dirty bool
+	err := db.QueryRowContext(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&current, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("no migrations applied, expected version %d", version)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read migration version: %w", err)
+	}
+
+	if dirty {
+		return fmt.Errorf("migration %d failed and left the schema dirty", current)
+	}
+	if current != version {
+		return fmt.Errorf("schema is at version %d, expected %d", current, version)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/golangtestcases/subscribe-service/internal/infra/metrics"
+)
+
+// PoolMetrics reports the connection pool statistics of the primary and the
+// replica, labelled db="primary" and db="replica". A nil replica is skipped.
+func PoolMetrics(primary, replica *sql.DB) metrics.Collector {
+	dbs := []struct {
+		name string
+		db   *sql.DB
+	}{
+		{"primary", primary},
+		{"replica", replica},
+	}
+
+	return metrics.CollectorFunc(func(context.Context) ([]metrics.Family, error) {
+		families := []metrics.Family{
+			{Name: "db_max_open_connections", Help: "Maximum number of open connections to the database.", Type: metrics.TypeGauge},
+			{Name: "db_open_connections", Help: "Number of established connections, in use and idle.", Type: metrics.TypeGauge},
+			{Name: "db_in_use_connections", Help: "Number of connections currently in use.", Type: metrics.TypeGauge},
+			{Name: "db_idle_connections", Help: "Number of idle connections.", Type: metrics.TypeGauge},
+			{Name: "db_wait_count_total", Help: "Number of times a query waited for a free connection.", Type: metrics.TypeCounter},
+			{Name: "db_wait_duration_seconds_total", Help: "Total time spent waiting for a free connection.", Type: metrics.TypeCounter},
+			{Name: "db_max_idle_closed_total", Help: "Number of connections closed due to SetMaxIdleConns.", Type: metrics.TypeCounter},
+			{Name: "db_max_idle_time_closed_total", Help: "Number of connections closed due to SetConnMaxIdleTime.", Type: metrics.TypeCounter},
+			{Name: "db_max_lifetime_closed_total", Help: "Number of connections closed due to SetConnMaxLifetime.", Type: metrics.TypeCounter},
+		}
+
+		for _, d := range dbs {
+			if d.db == nil {
+				continue
+			}
+
+			stats := d.db.Stats()
+			labels := []metrics.Label{{Name: "db", Value: d.name}}
+			values := []float64{
+				float64(stats.MaxOpenConnections),
+				float64(stats.OpenConnections),
+				float64(stats.InUse),
+				float64(stats.Idle),
+				float64(stats.WaitCount),
+				stats.WaitDuration.Seconds(),
+				float64(stats.MaxIdleClosed),
+				float64(stats.MaxIdleTimeClosed),
+				float64(stats.MaxLifetimeClosed),
+			}
+			for i, value := range values {
+				families[i].Samples = append(families[i].Samples, metrics.Sample{Labels: labels, Value: value})
+			}
+		}
+
+		return families, nil
+	})
+}
@@ -0,0 +1,95 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// ErrReplicaLagging is returned by ReadRouter.CheckReplica when the replica is
+// further behind the primary than allowed.
+var ErrReplicaLagging = errors.New("replica is lagging")
+
+// replicaLagQuery reports in seconds how far a streaming replica is behind.
+// A replica that has replayed everything it received is up to date even if the
+// last replayed transaction is old, as it is when the primary is idle.
+const replicaLagQuery = `
+	SELECT CASE
+		WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+		ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+	END
+`
+
+// ReadRouter sends read-only queries that tolerate slightly stale data, such as
+// reports, to a read replica while it is healthy and to the primary otherwise.
+// Writes never go through it. The replica is considered unhealthy until the
+// first successful CheckReplica.
+type ReadRouter struct {
+	primary *sql.DB
+	replica *sql.DB
+	maxLag  time.Duration
+	healthy atomic.Bool
+}
+
+// NewReadRouter returns a router over primary and replica. With a nil replica
+// every read goes to the primary.
+func NewReadRouter(primary, replica *sql.DB, maxLag time.Duration) *ReadRouter {
+	return &ReadRouter{
+		primary: primary,
+		replica: replica,
+		maxLag:  maxLag,
+	}
+}
+
+// Conn returns the transaction carried by ctx, if any, so that a transaction
+// reads its own writes. Otherwise it returns the replica when it is healthy and
+// the primary when it is not.
+func (r *ReadRouter) Conn(ctx context.Context) Querier {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tracedQuerier{q: tx}
+	}
+	if r.replica != nil && r.healthy.Load() {
+		return tracedQuerier{q: r.replica, instance: "replica"}
+	}
+	return tracedQuerier{q: r.primary}
+}
+
+// CheckReplica measures the replica lag and routes reads to the primary while
+// the replica is unreachable or lags more than the allowed maximum, and back
+// to the replica once it catches up.
+func (r *ReadRouter) CheckReplica(ctx context.Context) (time.Duration, error) {
+	if r.replica == nil {
+		return 0, nil
+	}
+
+	var seconds float64
+	if err := r.replica.QueryRowContext(ctx, replicaLagQuery).Scan(&seconds); err != nil {
+		r.healthy.Store(false)
+		return 0, fmt.Errorf("failed to query replica lag: %w", err)
+	}
+
+	lag := time.Duration(seconds * float64(time.Second))
+	if lag > r.maxLag {
+		r.healthy.Store(false)
+		return lag, fmt.Errorf("%w: %s behind, at most %s allowed", ErrReplicaLagging, lag, r.maxLag)
+	}
+
+	r.healthy.Store(true)
+	return lag, nil
+}
+
+// UsingReplica reports whether reads currently go to the replica.
+func (r *ReadRouter) UsingReplica() bool {
+	return r.replica != nil && r.healthy.Load()
+}
+
+// CheckReads reports an error while reads fall back to the primary.
+func (r *ReadRouter) CheckReads(context.Context) error {
+	if !r.UsingReplica() {
+		return errors.New("replica is unavailable or lagging, reads use the primary")
+	}
+	return nil
+}
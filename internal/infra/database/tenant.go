@@ -0,0 +1,149 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+
+	"github.com/golangtestcases/subscribe-service/internal/infra/tenant"
+)
+
+// tenantSetting is the PostgreSQL setting the row-level security policies
+// compare the tenant_id of rows with.
+const tenantSetting = "app.tenant_id"
+
+// NewTenantConnector wraps a PostgreSQL connector so that every statement runs
+// with app.tenant_id set to the tenant of its context. The row-level security
+// policies of the tenant tables then let a statement see and write only the
+// rows of that tenant, even if the query forgot to filter by it. Statements
+// without a tenant see no tenant rows at all.
+func NewTenantConnector(connector driver.Connector) driver.Connector {
+	return tenantConnector{Connector: connector}
+}
+
+type tenantConnector struct {
+	driver.Connector
+}
+
+func (c tenantConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &tenantConn{Conn: conn}, nil
+}
+
+// tenantConn remembers the tenant it last set, so that the setting is only
+// sent when the tenant changes. database/sql never uses a connection
+// concurrently, so the fields need no lock.
+type tenantConn struct {
+	driver.Conn
+	tenant string
+	known  bool
+}
+
+// setTenant sets app.tenant_id to the tenant of ctx unless it already is.
+func (c *tenantConn) setTenant(ctx context.Context) error {
+	id := tenant.ID(ctx)
+	if c.known && c.tenant == id {
+		return nil
+	}
+
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return errors.New("database: driver cannot execute statements with a context")
+	}
+	args := []driver.NamedValue{{Ordinal: 1, Value: tenantSetting}, {Ordinal: 2, Value: id}}
+	if _, err := execer.ExecContext(ctx, "SELECT set_config($1, $2, false)", args); err != nil {
+		return err
+	}
+
+	c.tenant, c.known = id, true
+	return nil
+}
+
+func (c *tenantConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	if err := c.setTenant(ctx); err != nil {
+		return nil, err
+	}
+	return execer.ExecContext(ctx, query, args)
+}
+
+func (c *tenantConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	if err := c.setTenant(ctx); err != nil {
+		return nil, err
+	}
+	return queryer.QueryContext(ctx, query, args)
+}
+
+func (c *tenantConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if err := c.setTenant(ctx); err != nil {
+		return nil, err
+	}
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return preparer.PrepareContext(ctx, query)
+	}
+	return c.Conn.Prepare(query)
+}
+
+// BeginTx sets the tenant before the transaction starts, so that rolling the
+// transaction back does not undo the setting.
+func (c *tenantConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if err := c.setTenant(ctx); err != nil {
+		return nil, err
+	}
+
+	var tx driver.Tx
+	var err error
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		tx, err = beginner.BeginTx(ctx, opts)
+	} else {
+		tx, err = c.Conn.Begin()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tenantTx{Tx: tx, conn: c}, nil
+}
+
+func (c *tenantConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *tenantConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *tenantConn) IsValid() bool {
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+// tenantTx forgets the tenant of its connection on rollback: a statement of
+// the transaction running for another tenant may have changed the setting,
+// and the rollback reverts that change.
+type tenantTx struct {
+	driver.Tx
+	conn *tenantConn
+}
+
+func (t *tenantTx) Rollback() error {
+	t.conn.known = false
+	return t.Tx.Rollback()
+}
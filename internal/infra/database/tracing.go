@@ -0,0 +1,64 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/golangtestcases/subscribe-service/internal/infra/tracing"
+)
+
+// tracedQuerier records a client span for every query made within a trace,
+// named after the SQL operation and carrying the statement without its
+// arguments. For QueryContext the span covers running the query, not reading
+// the rows.
+type tracedQuerier struct {
+	q        Querier
+	instance string
+}
+
+func (t tracedQuerier) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	ctx, span := t.start(ctx, query)
+	defer span.End()
+
+	result, err := t.q.ExecContext(ctx, query, args...)
+	span.RecordError(err)
+	return result, err
+}
+
+func (t tracedQuerier) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	ctx, span := t.start(ctx, query)
+	defer span.End()
+
+	rows, err := t.q.QueryContext(ctx, query, args...)
+	span.RecordError(err)
+	return rows, err
+}
+
+func (t tracedQuerier) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	ctx, span := t.start(ctx, query)
+	defer span.End()
+
+	row := t.q.QueryRowContext(ctx, query, args...)
+	span.RecordError(row.Err())
+	return row
+}
+
+func (t tracedQuerier) start(ctx context.Context, query string) (context.Context, *tracing.Span) {
+	if !tracing.SpanContextFromContext(ctx).IsValid() {
+		return ctx, nil
+	}
+
+	statement := strings.Join(strings.Fields(query), " ")
+	operation, _, _ := strings.Cut(statement, " ")
+
+	attributes := []tracing.Attribute{
+		tracing.String("db.operation.name", strings.ToUpper(operation)),
+		tracing.String("db.query.text", statement),
+	}
+	if t.instance != "" {
+		attributes = append(attributes, tracing.String("db.instance", t.instance))
+	}
+
+	return tracing.StartKind(ctx, strings.ToUpper(operation), tracing.SpanKindClient, attributes...)
+}
@@ -0,0 +1,66 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+)
+
+// Check tests one dependency of the service.
+type Check struct {
+	Name     string
+	Optional bool
+	Run      func(ctx context.Context) error
+}
+
+// Checker runs the readiness checks and remembers whether the service is
+// shutting down.
+type Checker struct {
+	checks       []Check
+	timeout      time.Duration
+	shuttingDown atomic.Bool
+}
+
+// NewChecker returns a checker running checks concurrently, each limited to
+// timeout.
+func NewChecker(timeout time.Duration, checks ...Check) *Checker {
+	return &Checker{
+		checks:  checks,
+		timeout: timeout,
+	}
+}
+
+// SetShuttingDown makes the service unready for good.
+func (c *Checker) SetShuttingDown() {
+	c.shuttingDown.Store(true)
+}
+
+func (c *Checker) Readiness(ctx context.Context) model.Readiness {
+	readiness := model.Readiness{
+		ShuttingDown: c.shuttingDown.Load(),
+		Dependencies: make([]model.DependencyHealth, len(c.checks)),
+	}
+
+	var wg sync.WaitGroup
+	for i, check := range c.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(ctx, c.timeout)
+			defer cancel()
+
+			readiness.Dependencies[i] = model.DependencyHealth{
+				Name:     check.Name,
+				Optional: check.Optional,
+				Error:    check.Run(ctx),
+			}
+		}()
+	}
+	wg.Wait()
+
+	return readiness
+}
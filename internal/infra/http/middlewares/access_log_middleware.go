@@ -0,0 +1,98 @@
+package middlewares
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/golangtestcases/subscribe-service/internal/infra/logging"
+	"github.com/google/uuid"
+)
+
+const (
+	RequestIDHeader = "X-Request-ID"
+
+	maxRequestIDLength = 128
+)
+
+// AccessLogMiddleware gives every request an ID, taken from the X-Request-ID
+// header if the client sent a usable one and generated otherwise, and echoes
+// it in the response. Handlers get a logger with the ID attached through
+// logging.FromContext, so that all lines of one request can be correlated.
+// Once the request is served it is logged with its route, status, size and
+// duration; requests to quiet routes, such as probes, only at debug level.
+type AccessLogMiddleware struct {
+	h           http.Handler
+	logger      *slog.Logger
+	quietRoutes map[string]bool
+}
+
+func NewAccessLogMiddleware(h http.Handler, logger *slog.Logger, quietRoutes ...string) http.Handler {
+	m := &AccessLogMiddleware{
+		h:           h,
+		logger:      logger,
+		quietRoutes: make(map[string]bool, len(quietRoutes)),
+	}
+	for _, route := range quietRoutes {
+		m.quietRoutes[route] = true
+	}
+	return m
+}
+
+func (m *AccessLogMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+
+	requestID := r.Header.Get(RequestIDHeader)
+	if !validRequestID(requestID) {
+		requestID = uuid.NewString()
+	}
+	w.Header().Set(RequestIDHeader, requestID)
+
+	logger := m.logger.With("request_id", requestID)
+	ctx := logging.WithRequestID(r.Context(), requestID)
+	ctx = logging.WithLogger(ctx, logger)
+	r = r.WithContext(ctx)
+
+	recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+	m.h.ServeHTTP(recorder, r)
+
+	// The mux sets the pattern on the request it was given, which is r.
+	route := r.Pattern
+	if route == "" {
+		route = unmatchedRoute
+	}
+
+	level := slog.LevelInfo
+	switch {
+	case recorder.status >= http.StatusInternalServerError:
+		level = slog.LevelError
+	case m.quietRoutes[route]:
+		level = slog.LevelDebug
+	}
+
+	logger.Log(ctx, level, "request handled",
+		"method", r.Method,
+		"route", route,
+		"path", r.URL.Path,
+		"status", recorder.status,
+		"bytes", recorder.bytes,
+		"duration_ms", float64(time.Since(start).Microseconds())/1000,
+	)
+}
+
+// validRequestID accepts IDs of reasonable length made of characters that are
+// safe to log and to echo in a header.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
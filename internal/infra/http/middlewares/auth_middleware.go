@@ -0,0 +1,79 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/access"
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/infra/http/problem"
+	"github.com/golangtestcases/subscribe-service/internal/infra/logging"
+)
+
+// Authenticator resolves the credentials presented in a bearer token. It
+// returns access.ErrInvalidCredentials for credentials that identify no one.
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (model.Identity, error)
+}
+
+// AuthMiddleware admits requests with a valid bearer token and answers the
+// others with 401. The caller's identity is put on the request context and
+// its subject on the request-scoped logger; what the caller may do is left to
+// AuthorizationMiddleware.
+type AuthMiddleware struct {
+	h             http.Handler
+	authenticator Authenticator
+	logger        *slog.Logger
+}
+
+func NewAuthMiddleware(h http.Handler, authenticator Authenticator, logger *slog.Logger) http.Handler {
+	return &AuthMiddleware{
+		h:             h,
+		authenticator: authenticator,
+		logger:        logger,
+	}
+}
+
+func (m *AuthMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context(), m.logger)
+
+	token, ok := bearerToken(r)
+	if !ok {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="subscribe-service"`)
+		problem.Write(w, r, http.StatusUnauthorized, "missing bearer token")
+		return
+	}
+
+	identity, err := m.authenticator.Authenticate(r.Context(), token)
+	if err != nil {
+		if errors.Is(err, access.ErrInvalidCredentials) {
+			logger.Info("rejected credentials", "error", err)
+			w.Header().Set("WWW-Authenticate", `Bearer realm="subscribe-service", error="invalid_token"`)
+			problem.Write(w, r, http.StatusUnauthorized, err.Error())
+			return
+		}
+		logger.Error("failed to authenticate", "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, "")
+		return
+	}
+
+	logger = logger.With("subject", identity.Subject)
+	ctx := access.WithIdentity(r.Context(), identity)
+	ctx = logging.WithLogger(ctx, logger)
+	m.h.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// bearerToken returns the token of an "Authorization: Bearer <token>" header.
+// The scheme is case-insensitive, as in RFC 7235.
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
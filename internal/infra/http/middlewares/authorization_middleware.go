@@ -0,0 +1,73 @@
+package middlewares
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/access"
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/infra/http/problem"
+	"github.com/golangtestcases/subscribe-service/internal/infra/logging"
+)
+
+// Policy lists the roles allowed to call each route, keyed by the pattern the
+// route is registered with, such as "DELETE /api/subscriptions/{id}".
+type Policy map[string][]model.Role
+
+// AuthorizationMiddleware admits callers holding one of the roles the policy
+// lists for the matched route and answers the others with 403 and the reason.
+// Routes missing from the policy are closed to everyone. It must run inside
+// the ServeMux, where the request carries its pattern, and after
+// AuthMiddleware, which puts the caller's identity on the context.
+type AuthorizationMiddleware struct {
+	h      http.Handler
+	policy Policy
+	logger *slog.Logger
+}
+
+func NewAuthorizationMiddleware(h http.Handler, policy Policy, logger *slog.Logger) http.Handler {
+	return &AuthorizationMiddleware{
+		h:      h,
+		policy: policy,
+		logger: logger,
+	}
+}
+
+func (m *AuthorizationMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context(), m.logger)
+
+	roles, ok := m.policy[r.Pattern]
+	if !ok {
+		logger.Error("no access policy for route", "route", r.Pattern)
+		problem.Write(w, r, http.StatusForbidden, fmt.Sprintf("%s is not open to any role", r.Pattern))
+		return
+	}
+
+	identity, _ := access.IdentityFromContext(r.Context())
+	if !identity.HasRole(roles...) {
+		logger.Info("access denied", "route", r.Pattern, "roles", identity.Roles)
+		problem.Write(w, r, http.StatusForbidden, fmt.Sprintf("%s requires %s; the caller has %s", r.Pattern, describeRoles(roles), describeRoles(identity.Roles)))
+		return
+	}
+
+	m.h.ServeHTTP(w, r)
+}
+
+// describeRoles returns "the admin role" or "one of the roles editor, admin".
+func describeRoles(roles []model.Role) string {
+	names := make([]string, 0, len(roles))
+	for _, role := range roles {
+		names = append(names, string(role))
+	}
+
+	switch len(names) {
+	case 0:
+		return "no role"
+	case 1:
+		return fmt.Sprintf("the %s role", names[0])
+	default:
+		return "one of the roles " + strings.Join(names, ", ")
+	}
+}
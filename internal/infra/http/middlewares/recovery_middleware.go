@@ -0,0 +1,54 @@
+package middlewares
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/golangtestcases/subscribe-service/internal/infra/http/problem"
+	"github.com/golangtestcases/subscribe-service/internal/infra/logging"
+)
+
+// RecoveryMiddleware turns a panicking handler into a logged 500 instead of a
+// dropped connection. It must wrap the ServeMux directly, so that the panic is
+// caught before the middlewares that record the status code see the response.
+type RecoveryMiddleware struct {
+	h      http.Handler
+	logger *slog.Logger
+}
+
+func NewRecoveryMiddleware(h http.Handler, logger *slog.Logger) http.Handler {
+	return &RecoveryMiddleware{h: h, logger: logger}
+}
+
+func (m *RecoveryMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+	defer func() {
+		recovered := recover()
+		if recovered == nil {
+			return
+		}
+		// http.ErrAbortHandler deliberately aborts the response and is not
+		// worth a stack trace; the server closes the connection.
+		if err, ok := recovered.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+			panic(recovered)
+		}
+
+		logger := logging.FromContext(r.Context(), m.logger)
+		logger.Error("panic while handling request",
+			"panic", fmt.Sprint(recovered),
+			"stack", string(debug.Stack()),
+		)
+
+		// Once the status line is out, all that can be done is to cut the
+		// response short.
+		if recorder.wroteHeader {
+			panic(http.ErrAbortHandler)
+		}
+		problem.Write(recorder, r, http.StatusInternalServerError, "")
+	}()
+
+	m.h.ServeHTTP(recorder, r)
+}
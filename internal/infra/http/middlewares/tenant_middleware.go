@@ -0,0 +1,72 @@
+package middlewares
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/access"
+	"github.com/golangtestcases/subscribe-service/internal/infra/http/problem"
+	"github.com/golangtestcases/subscribe-service/internal/infra/logging"
+	"github.com/golangtestcases/subscribe-service/internal/infra/tenant"
+)
+
+// TenantMiddleware resolves the tenant a request acts for and puts it on the
+// request context and its ID on the request-scoped logger. A credential bound
+// to a tenant decides it, and a header naming another tenant is answered with
+// 403. Other callers name the tenant in the header, which may be left out
+// when there is only one. Unknown tenants are answered with 400. It must run
+// after AuthMiddleware, if authentication is on.
+type TenantMiddleware struct {
+	h       http.Handler
+	tenants *tenant.Directory
+	header  string
+	logger  *slog.Logger
+}
+
+func NewTenantMiddleware(h http.Handler, tenants *tenant.Directory, header string, logger *slog.Logger) http.Handler {
+	return &TenantMiddleware{
+		h:       h,
+		tenants: tenants,
+		header:  header,
+		logger:  logger,
+	}
+}
+
+func (m *TenantMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context(), m.logger)
+
+	requested := r.Header.Get(m.header)
+	identity, _ := access.IdentityFromContext(r.Context())
+
+	id := requested
+	if identity.TenantID != "" {
+		if requested != "" && requested != identity.TenantID {
+			logger.Info("access denied", "tenant", requested, "credential_tenant", identity.TenantID)
+			problem.Write(w, r, http.StatusForbidden, fmt.Sprintf("the credential belongs to another tenant than %q", requested))
+			return
+		}
+		id = identity.TenantID
+	}
+
+	if id == "" {
+		single, ok := m.tenants.Single()
+		if !ok {
+			problem.Write(w, r, http.StatusBadRequest, fmt.Sprintf("the %s header is required", m.header))
+			return
+		}
+		id = single.ID
+	}
+
+	t, ok := m.tenants.Lookup(id)
+	if !ok {
+		logger.Info("unknown tenant", "tenant", id)
+		problem.Write(w, r, http.StatusBadRequest, fmt.Sprintf("unknown tenant %q", id))
+		return
+	}
+
+	logger = logger.With("tenant", t.ID)
+	ctx := tenant.WithTenant(r.Context(), t)
+	ctx = logging.WithLogger(ctx, logger)
+	m.h.ServeHTTP(w, r.WithContext(ctx))
+}
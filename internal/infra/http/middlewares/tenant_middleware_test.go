@@ -0,0 +1,65 @@
+package middlewares_test
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/access"
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+	"github.com/golangtestcases/subscribe-service/internal/infra/http/middlewares"
+	"github.com/golangtestcases/subscribe-service/internal/infra/tenant"
+)
+
+func TestTenantMiddleware(t *testing.T) {
+	tenants := tenant.NewDirectory([]model.Tenant{
+		{ID: "acme", Name: "Acme", DefaultCurrency: "RUB"},
+		{ID: "globex", Name: "Globex", DefaultCurrency: "USD"},
+	})
+	handler := middlewares.NewTenantMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, tenant.ID(r.Context()))
+	}), tenants, "X-Tenant-ID", slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	tests := []struct {
+		name           string
+		identityTenant string
+		header         string
+		wantStatus     int
+		wantTenant     string
+	}{
+		{name: "token tenant", identityTenant: "acme", wantStatus: http.StatusOK, wantTenant: "acme"},
+		{name: "token tenant named in the header", identityTenant: "acme", header: "acme", wantStatus: http.StatusOK, wantTenant: "acme"},
+		{name: "token of another tenant", identityTenant: "globex", header: "acme", wantStatus: http.StatusForbidden},
+		{name: "unbound caller naming a tenant", header: "globex", wantStatus: http.StatusOK, wantTenant: "globex"},
+		{name: "unbound caller without a header", wantStatus: http.StatusBadRequest},
+		{name: "unknown tenant", header: "initech", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/api/subscriptions", nil)
+			if tt.header != "" {
+				r.Header.Set("X-Tenant-ID", tt.header)
+			}
+			if tt.identityTenant != "" {
+				r = r.WithContext(access.WithIdentity(r.Context(), model.Identity{
+					Subject:  "user:test",
+					Roles:    []model.Role{model.RoleEditor},
+					TenantID: tt.identityTenant,
+				}))
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("got status %d, want %d: %s", w.Code, tt.wantStatus, w.Body)
+			}
+			if tt.wantStatus == http.StatusOK && w.Body.String() != tt.wantTenant {
+				t.Fatalf("got tenant %q, want %q", w.Body, tt.wantTenant)
+			}
+		})
+	}
+}
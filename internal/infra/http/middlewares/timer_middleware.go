@@ -1,23 +1,54 @@
 package middlewares
 
 import (
-	"fmt"
 	"net/http"
+	"strconv"
 	"time"
+
+	"github.com/golangtestcases/subscribe-service/internal/infra/metrics"
 )
 
+// unmatchedRoute labels requests that matched no route, so that random URLs do
+// not create new series.
+const unmatchedRoute = "unmatched"
+
+// TimerMiddleware counts requests and measures their latency per route. It
+// must wrap the ServeMux or a middleware passing the request on unchanged: the
+// route is the pattern the mux matched, such as "GET /api/subscriptions/{id}",
+// never the raw URL with ids in it.
 type TimerMiddleware struct {
-	h http.Handler
+	h        http.Handler
+	requests *metrics.CounterVec
+	duration *metrics.HistogramVec
+	inFlight *metrics.GaugeVec
 }
 
-func NewTimerMiddleware(h http.Handler) http.Handler {
-	return &TimerMiddleware{h: h}
+func NewTimerMiddleware(h http.Handler, registry *metrics.Registry) http.Handler {
+	return &TimerMiddleware{
+		h: h,
+		requests: registry.NewCounterVec("http_requests_total",
+			"Number of HTTP requests by route and status code.", "method", "route", "status"),
+		duration: registry.NewHistogramVec("http_request_duration_seconds",
+			"Latency of HTTP requests by route.", metrics.DefBuckets, "method", "route"),
+		inFlight: registry.NewGaugeVec("http_requests_in_flight",
+			"Number of HTTP requests being served."),
+	}
 }
 
 func (m *TimerMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.inFlight.Add(1)
+	defer m.inFlight.Add(-1)
+
+	recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
 	defer func(now time.Time) {
-		fmt.Printf("%s spent %s\n", r.URL.String(), time.Since(now))
+		route := r.Pattern
+		if route == "" {
+			route = unmatchedRoute
+		}
+
+		m.requests.Inc(r.Method, route, strconv.Itoa(recorder.status))
+		m.duration.Observe(time.Since(now).Seconds(), r.Method, route)
 	}(time.Now())
 
-	m.h.ServeHTTP(w, r)
-}
\ No newline at end of file
+	m.h.ServeHTTP(recorder, r)
+}
@@ -0,0 +1,53 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/golangtestcases/subscribe-service/internal/infra/logging"
+	"github.com/golangtestcases/subscribe-service/internal/infra/tracing"
+)
+
+// TracingMiddleware starts a server span for every request, continuing the
+// caller's trace if the request has a traceparent header. It must wrap the
+// ServeMux or a middleware passing the request on unchanged, so that the span
+// is named after the matched route. Inside AccessLogMiddleware it adds the
+// trace ID to the request-scoped logger and the request ID to the span.
+type TracingMiddleware struct {
+	h      http.Handler
+	tracer *tracing.Tracer
+}
+
+func NewTracingMiddleware(h http.Handler, tracer *tracing.Tracer) http.Handler {
+	return &TracingMiddleware{h: h, tracer: tracer}
+}
+
+func (m *TracingMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := m.tracer.StartRoot(r.Context(), tracing.Extract(r.Header), r.Method, tracing.SpanKindServer,
+		tracing.String("http.request.method", r.Method),
+		tracing.String("url.path", r.URL.Path),
+	)
+	defer span.End()
+
+	if requestID := logging.RequestID(ctx); requestID != "" {
+		span.SetAttributes(tracing.String("http.request.id", requestID))
+	}
+	if logger := logging.FromContext(ctx, nil); logger != nil {
+		ctx = logging.WithLogger(ctx, logger.With("trace_id", tracing.SpanContextFromContext(ctx).TraceID.String()))
+	}
+	traced := r.WithContext(ctx)
+
+	recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+	m.h.ServeHTTP(recorder, traced)
+
+	// Hand the matched route back to the middlewares wrapping this one.
+	r.Pattern = traced.Pattern
+	if r.Pattern != "" {
+		span.SetName(r.Pattern)
+		span.SetAttributes(tracing.String("http.route", r.Pattern))
+	}
+	span.SetAttributes(tracing.Int("http.response.status_code", recorder.status))
+	if recorder.status >= http.StatusInternalServerError {
+		span.RecordError(fmt.Errorf("HTTP %d", recorder.status))
+	}
+}
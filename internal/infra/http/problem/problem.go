@@ -0,0 +1,47 @@
+// Package problem writes error responses as RFC 7807 problem details, so that
+// every endpoint fails with the same application/problem+json body.
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/golangtestcases/subscribe-service/internal/infra/logging"
+)
+
+const ContentType = "application/problem+json"
+
+// Details is the body of an error response. Type is always "about:blank": the
+// status code and Title say what went wrong and Detail explains this
+// occurrence.
+type Details struct {
+	Type      string `json:"type" example:"about:blank"`
+	Title     string `json:"title" example:"Bad Request"`
+	Status    int    `json:"status" example:"400"`
+	Detail    string `json:"detail,omitempty" example:"invalid subscription id"`
+	Instance  string `json:"instance,omitempty" example:"/api/subscriptions/42"`
+	RequestID string `json:"request_id,omitempty" example:"0b5a3c1e-8d2f-4f6a-9c7e-1a2b3c4d5e6f"`
+}
+
+// Write responds with status and a problem body for r. detail may be empty
+// when the title says it all, as for internal server errors whose cause must
+// not be shown to clients.
+func Write(w http.ResponseWriter, r *http.Request, status int, detail string) {
+	details := Details{
+		Type:      "about:blank",
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    detail,
+		Instance:  r.URL.Path,
+		RequestID: logging.RequestID(r.Context()),
+	}
+
+	h := w.Header()
+	// Drop headers set for the successful response, as http.Error does.
+	h.Del("Content-Length")
+	h.Set("Content-Type", ContentType)
+	h.Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(details)
+}
@@ -0,0 +1,340 @@
+// Package metrics keeps counters, gauges and histograms and writes them in the
+// Prometheus text exposition format (version 0.0.4). It covers what the
+// service needs without depending on the Prometheus client library.
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type Type string
+
+const (
+	TypeCounter   Type = "counter"
+	TypeGauge     Type = "gauge"
+	TypeHistogram Type = "histogram"
+)
+
+// DefBuckets are the default latency buckets of the Prometheus client, in
+// seconds.
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Family is a metric with all its series, as written on a scrape.
+type Family struct {
+	Name    string
+	Help    string
+	Type    Type
+	Samples []Sample
+}
+
+// Sample is one line of a family. Suffix is appended to the family name, as
+// "_bucket", "_sum" and "_count" are for histograms.
+type Sample struct {
+	Suffix string
+	Labels []Label
+	Value  float64
+}
+
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Collector produces families on every scrape.
+type Collector interface {
+	Collect(ctx context.Context) ([]Family, error)
+}
+
+// CollectorFunc computes families when scraped, for values read from
+// elsewhere such as connection pool statistics.
+type CollectorFunc func(ctx context.Context) ([]Family, error)
+
+func (f CollectorFunc) Collect(ctx context.Context) ([]Family, error) {
+	return f(ctx)
+}
+
+type Registry struct {
+	mu         sync.Mutex
+	collectors []Collector
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+func (r *Registry) Register(c Collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.collectors = append(r.collectors, c)
+}
+
+func (r *Registry) NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	c := &CounterVec{vec: newVec(name, help, TypeCounter, labelNames)}
+	r.Register(c)
+	return c
+}
+
+func (r *Registry) NewGaugeVec(name, help string, labelNames ...string) *GaugeVec {
+	g := &GaugeVec{vec: newVec(name, help, TypeGauge, labelNames)}
+	r.Register(g)
+	return g
+}
+
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	h := &HistogramVec{vec: newVec(name, help, TypeHistogram, labelNames), buckets: buckets}
+	r.Register(h)
+	return h
+}
+
+// WriteTo writes every family sorted by name. A collector that fails is
+// skipped and its error returned once the rest has been written, so that one
+// broken source does not hide all other metrics.
+func (r *Registry) WriteTo(ctx context.Context, w io.Writer) error {
+	r.mu.Lock()
+	collectors := append([]Collector(nil), r.collectors...)
+	r.mu.Unlock()
+
+	var families []Family
+	var errs []error
+	for _, c := range collectors {
+		collected, err := c.Collect(ctx)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		families = append(families, collected...)
+	}
+
+	sort.SliceStable(families, func(i, j int) bool {
+		return families[i].Name < families[j].Name
+	})
+
+	bw := bufio.NewWriter(w)
+	for _, family := range families {
+		writeFamily(bw, family)
+	}
+	if err := bw.Flush(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+func writeFamily(w *bufio.Writer, family Family) {
+	fmt.Fprintf(w, "# HELP %s %s\n", family.Name, escapeHelp(family.Help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", family.Name, family.Type)
+
+	for _, sample := range family.Samples {
+		w.WriteString(family.Name)
+		w.WriteString(sample.Suffix)
+		if len(sample.Labels) > 0 {
+			w.WriteByte('{')
+			for i, label := range sample.Labels {
+				if i > 0 {
+					w.WriteByte(',')
+				}
+				fmt.Fprintf(w, "%s=\"%s\"", label.Name, escapeLabelValue(label.Value))
+			}
+			w.WriteByte('}')
+		}
+		w.WriteByte(' ')
+		w.WriteString(formatValue(sample.Value))
+		w.WriteByte('\n')
+	}
+}
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string {
+	return helpEscaper.Replace(s)
+}
+
+func escapeLabelValue(s string) string {
+	return labelEscaper.Replace(s)
+}
+
+// vec keeps one series per combination of label values, in the order the
+// combinations were first seen.
+type vec struct {
+	name       string
+	help       string
+	typ        Type
+	labelNames []string
+
+	mu     sync.Mutex
+	series map[string]*series
+	order  []string
+}
+
+type series struct {
+	labels []Label
+	value  float64
+	// Histograms only: counts per bucket, not cumulative, and the sum.
+	counts []uint64
+	sum    float64
+}
+
+func newVec(name, help string, typ Type, labelNames []string) vec {
+	return vec{
+		name:       name,
+		help:       help,
+		typ:        typ,
+		labelNames: labelNames,
+		series:     make(map[string]*series),
+	}
+}
+
+// with returns the series for the label values, creating it if needed. The
+// caller must hold v.mu.
+func (v *vec) with(labelValues []string) *series {
+	if len(labelValues) != len(v.labelNames) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", v.name, len(v.labelNames), len(labelValues)))
+	}
+
+	key := strings.Join(labelValues, "\xff")
+	s, ok := v.series[key]
+	if !ok {
+		s = &series{labels: make([]Label, len(labelValues))}
+		for i, value := range labelValues {
+			s.labels[i] = Label{Name: v.labelNames[i], Value: value}
+		}
+		v.series[key] = s
+		v.order = append(v.order, key)
+	}
+	return s
+}
+
+func (v *vec) collectValues() []Family {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	family := Family{Name: v.name, Help: v.help, Type: v.typ}
+	for _, key := range v.order {
+		s := v.series[key]
+		family.Samples = append(family.Samples, Sample{Labels: s.labels, Value: s.value})
+	}
+	return []Family{family}
+}
+
+type CounterVec struct {
+	vec
+}
+
+// Add increases the counter by delta, which must not be negative.
+func (c *CounterVec) Add(delta float64, labelValues ...string) {
+	if delta < 0 {
+		panic(fmt.Sprintf("metrics: counter %s cannot decrease", c.name))
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.with(labelValues).value += delta
+}
+
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+func (c *CounterVec) Collect(context.Context) ([]Family, error) {
+	return c.collectValues(), nil
+}
+
+type GaugeVec struct {
+	vec
+}
+
+func (g *GaugeVec) Set(value float64, labelValues ...string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.with(labelValues).value = value
+}
+
+func (g *GaugeVec) Add(delta float64, labelValues ...string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.with(labelValues).value += delta
+}
+
+func (g *GaugeVec) Collect(context.Context) ([]Family, error) {
+	return g.collectValues(), nil
+}
+
+type HistogramVec struct {
+	vec
+	buckets []float64
+}
+
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s := h.with(labelValues)
+	if s.counts == nil {
+		s.counts = make([]uint64, len(h.buckets)+1)
+	}
+
+	// The last count is for values above every bucket, that is +Inf only.
+	i := sort.SearchFloat64s(h.buckets, value)
+	s.counts[i]++
+	s.sum += value
+}
+
+func (h *HistogramVec) Collect(context.Context) ([]Family, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	family := Family{Name: h.name, Help: h.help, Type: TypeHistogram}
+	for _, key := range h.order {
+		s := h.series[key]
+
+		var cumulative uint64
+		for i, upperBound := range h.buckets {
+			cumulative += s.counts[i]
+			family.Samples = append(family.Samples, Sample{
+				Suffix: "_bucket",
+				Labels: withLabel(s.labels, "le", formatValue(upperBound)),
+				Value:  float64(cumulative),
+			})
+		}
+		cumulative += s.counts[len(h.buckets)]
+
+		family.Samples = append(family.Samples,
+			Sample{Suffix: "_bucket", Labels: withLabel(s.labels, "le", "+Inf"), Value: float64(cumulative)},
+			Sample{Suffix: "_sum", Labels: s.labels, Value: s.sum},
+			Sample{Suffix: "_count", Labels: s.labels, Value: float64(cumulative)},
+		)
+	}
+	return []Family{family}, nil
+}
+
+func withLabel(labels []Label, name, value string) []Label {
+	return append(append(make([]Label, 0, len(labels)+1), labels...), Label{Name: name, Value: value})
+}
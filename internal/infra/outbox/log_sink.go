@@ -0,0 +1,31 @@
+package outbox
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+)
+
+// LogSink writes every event to the log.
+type LogSink struct {
+	logger *slog.Logger
+}
+
+func NewLogSink(logger *slog.Logger) *LogSink {
+	return &LogSink{logger: logger}
+}
+
+func (s *LogSink) Name() string {
+	return "log"
+}
+
+func (s *LogSink) Publish(_ context.Context, event model.Event) error {
+	s.logger.Info("event",
+		"event_id", event.ID,
+		"type", event.Type,
+		"occurred_at", event.OccurredAt,
+		"data", string(event.Payload),
+	)
+	return nil
+}
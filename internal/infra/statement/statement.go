@@ -0,0 +1,232 @@
+package statement
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+)
+
+var (
+	dateColumns        = []string{"date", "transaction date", "posting date", "posted", "дата", "дата операции", "дата платежа"}
+	descriptionColumns = []string{"description", "merchant", "payee", "name", "details", "описание", "назначение", "описание операции", "контрагент"}
+	amountColumns      = []string{"amount", "sum", "сумма", "сумма операции", "сумма платежа"}
+
+	dateLayouts = []string{
+		"2006-01-02", "2006-01-02 15:04:05", "2006-01-02T15:04:05Z07:00",
+		"02.01.2006", "02.01.2006 15:04", "02.01.2006 15:04:05",
+		"01/02/2006", "2006/01/02",
+	}
+
+	ofxTransaction = regexp.MustCompile(`(?is)<STMTTRN>(.*?)</STMTTRN>`)
+)
+
+// Parse reads a bank statement in OFX or CSV format, detected by content.
+func Parse(r io.Reader) ([]model.Transaction, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	upper := bytes.ToUpper(data[:min(len(data), 4096)])
+	if bytes.Contains(upper, []byte("OFXHEADER")) || bytes.Contains(upper, []byte("<OFX>")) {
+		return ParseOFX(bytes.NewReader(data))
+	}
+	return ParseCSV(bytes.NewReader(data))
+}
+
+// ParseCSV reads a CSV statement with a header row. Date, description and
+// amount columns are found by their names in English or Russian; comma and
+// semicolon separators are supported.
+func ParseCSV(r io.Reader) ([]model.Transaction, error) {
+	br := bufio.NewReader(r)
+	firstLine, err := br.Peek(min(br.Size(), 1024))
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return nil, err
+	}
+
+	reader := csv.NewReader(br)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if line, _, _ := bytes.Cut(firstLine, []byte("\n")); bytes.Count(line, []byte(";")) > bytes.Count(line, []byte(",")) {
+		reader.Comma = ';'
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+
+	dateIdx := findColumn(header, dateColumns)
+	descriptionIdx := findColumn(header, descriptionColumns)
+	amountIdx := findColumn(header, amountColumns)
+	if dateIdx < 0 || descriptionIdx < 0 || amountIdx < 0 {
+		return nil, errors.New("statement must have date, description and amount columns")
+	}
+
+	var transactions []model.Transaction
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read row: %w", err)
+		}
+		if len(record) <= max(dateIdx, descriptionIdx, amountIdx) {
+			continue
+		}
+
+		line, _ := reader.FieldPos(0)
+
+		date, err := parseDate(record[dateIdx])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		amount, err := parseAmount(record[amountIdx])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		transactions = append(transactions, model.Transaction{
+			Date:        date,
+			Description: strings.TrimSpace(record[descriptionIdx]),
+			Amount:      amount,
+		})
+	}
+
+	return transactions, nil
+}
+
+// ParseOFX reads the STMTTRN records of an OFX statement. Both the SGML
+// flavour (OFX 1.x, tags without closing pairs) and the XML one are accepted.
+func ParseOFX(r io.Reader) ([]model.Transaction, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var transactions []model.Transaction
+	for _, match := range ofxTransaction.FindAllSubmatch(data, -1) {
+		block := string(match[1])
+
+		dateStr := ofxValue(block, "DTPOSTED")
+		if len(dateStr) < 8 {
+			return nil, fmt.Errorf("invalid DTPOSTED %q", dateStr)
+		}
+		date, err := time.Parse("20060102", dateStr[:8])
+		if err != nil {
+			return nil, fmt.Errorf("invalid DTPOSTED %q: %w", dateStr, err)
+		}
+
+		amount, err := parseAmount(ofxValue(block, "TRNAMT"))
+		if err != nil {
+			return nil, err
+		}
+
+		description := ofxValue(block, "NAME")
+		if description == "" {
+			description = ofxValue(block, "MEMO")
+		}
+
+		transactions = append(transactions, model.Transaction{
+			Date:        date,
+			Description: description,
+			Amount:      amount,
+		})
+	}
+
+	if len(transactions) == 0 {
+		return nil, errors.New("no transactions found")
+	}
+	return transactions, nil
+}
+
+func ofxValue(block, tag string) string {
+	start := strings.Index(upperASCII(block), "<"+tag+">")
+	if start < 0 {
+		return ""
+	}
+	value := block[start+len(tag)+2:]
+	if end := strings.IndexAny(value, "<\r\n"); end >= 0 {
+		value = value[:end]
+	}
+	return strings.TrimSpace(value)
+}
+
+// upperASCII upper-cases only ASCII letters, which is enough for OFX tags. It
+// keeps the byte length of s, so that offsets found in the result are valid
+// in s; strings.ToUpper changes the length of runes such as 'ı' and 'ſ'.
+func upperASCII(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		if 'a' <= c && c <= 'z' {
+			b[i] = c - 'a' + 'A'
+		}
+	}
+	return string(b)
+}
+
+func findColumn(header []string, names []string) int {
+	for i, column := range header {
+		column = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")))
+		for _, name := range names {
+			if column == name {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func parseDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range dateLayouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unsupported date %q", value)
+}
+
+// parseAmount accepts amounts such as "-799.00", "1 234,56", "1.234,56" and
+// "-299,00 RUB".
+func parseAmount(value string) (float64, error) {
+	cleaned := strings.Map(func(r rune) rune {
+		switch {
+		case r >= '0' && r <= '9', r == '-', r == '.', r == ',':
+			return r
+		case r == '−':
+			return '-'
+		default:
+			return -1
+		}
+	}, value)
+
+	// With both separators present, the one that comes last is the decimal
+	// mark and the other groups thousands: "1,234.56" and "1.234,56".
+	comma, dot := strings.LastIndex(cleaned, ","), strings.LastIndex(cleaned, ".")
+	switch {
+	case comma >= 0 && dot >= 0 && comma > dot:
+		cleaned = strings.ReplaceAll(cleaned, ".", "")
+		cleaned = strings.Replace(cleaned, ",", ".", 1)
+	case comma >= 0 && dot >= 0:
+		cleaned = strings.ReplaceAll(cleaned, ",", "")
+	case comma >= 0:
+		cleaned = strings.ReplaceAll(cleaned, ",", ".")
+	}
+
+	amount, err := strconv.ParseFloat(cleaned, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", value)
+	}
+	return amount, nil
+}
@@ -0,0 +1,107 @@
+package tenant
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+)
+
+var (
+	// idPattern keeps tenant IDs safe to put in headers, logs and metric
+	// labels.
+	idPattern       = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+	currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+)
+
+// Directory holds the settings of the tenants the service runs for.
+type Directory struct {
+	tenants []model.Tenant
+	byID    map[string]model.Tenant
+}
+
+// NewDirectory returns a directory of the tenants, which must have unique
+// IDs.
+func NewDirectory(tenants []model.Tenant) *Directory {
+	d := &Directory{
+		tenants: tenants,
+		byID:    make(map[string]model.Tenant, len(tenants)),
+	}
+	for _, tenant := range tenants {
+		d.byID[tenant.ID] = tenant
+	}
+	return d
+}
+
+// Lookup returns the tenant with the ID and whether there is one.
+func (d *Directory) Lookup(id string) (model.Tenant, bool) {
+	tenant, ok := d.byID[id]
+	return tenant, ok
+}
+
+// Tenants returns every tenant in the order they were configured.
+func (d *Directory) Tenants() []model.Tenant {
+	return d.tenants
+}
+
+// Single returns the only tenant and true if there is exactly one, which
+// requests then act for without naming it.
+func (d *Directory) Single() (model.Tenant, bool) {
+	if len(d.tenants) != 1 {
+		return model.Tenant{}, false
+	}
+	return d.tenants[0], true
+}
+
+type tenantsFile struct {
+	Tenants []model.Tenant `json:"tenants"`
+}
+
+// ReadFile returns the tenants listed in a JSON file of the form
+//
+//	{"tenants": [{"id": "acme", "name": "Acme", "default_currency": "USD"}]}
+//
+// Tenants without a default_currency get defaultCurrency.
+func ReadFile(path, defaultCurrency string) ([]model.Tenant, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file tenantsFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(file.Tenants) == 0 {
+		return nil, fmt.Errorf("%s: no tenants", path)
+	}
+
+	seen := make(map[string]bool, len(file.Tenants))
+	for i, tenant := range file.Tenants {
+		if !idPattern.MatchString(tenant.ID) {
+			return nil, fmt.Errorf("%s: tenant %d: invalid id %q: must be lowercase letters, digits, '-' and '_'", path, i, tenant.ID)
+		}
+		if seen[tenant.ID] {
+			return nil, fmt.Errorf("%s: duplicate tenant %q", path, tenant.ID)
+		}
+		seen[tenant.ID] = true
+
+		if tenant.Name == "" {
+			file.Tenants[i].Name = tenant.ID
+		}
+		if tenant.DefaultCurrency == "" {
+			file.Tenants[i].DefaultCurrency = defaultCurrency
+		} else if !ValidCurrency(tenant.DefaultCurrency) {
+			return nil, fmt.Errorf("%s: tenant %q: invalid default_currency %q: must be an ISO 4217 code such as RUB", path, tenant.ID, tenant.DefaultCurrency)
+		}
+	}
+
+	return file.Tenants, nil
+}
+
+// ValidCurrency reports whether code looks like an ISO 4217 currency code.
+func ValidCurrency(code string) bool {
+	return currencyPattern.MatchString(code)
+}
@@ -0,0 +1,34 @@
+// Package tenant carries the tenant a request or a background job acts for in
+// a context. Repositories scope every query by the tenant of the context, and
+// PostgreSQL enforces the same scope with row-level security.
+package tenant
+
+import (
+	"context"
+
+	"github.com/golangtestcases/subscribe-service/internal/domain/model"
+)
+
+// DefaultID is the tenant of installations that do not configure tenants.
+// Data stored before tenants were introduced belongs to it.
+const DefaultID = "default"
+
+type tenantKey struct{}
+
+// WithTenant returns a copy of ctx acting for the tenant.
+func WithTenant(ctx context.Context, tenant model.Tenant) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// FromContext returns the tenant carried by ctx and whether there is one.
+func FromContext(ctx context.Context) (model.Tenant, bool) {
+	tenant, ok := ctx.Value(tenantKey{}).(model.Tenant)
+	return tenant, ok
+}
+
+// ID returns the ID of the tenant carried by ctx, or an empty string if there
+// is none. Queries made for the empty tenant match no data.
+func ID(ctx context.Context) string {
+	tenant, _ := FromContext(ctx)
+	return tenant.ID
+}
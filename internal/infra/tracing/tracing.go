@@ -0,0 +1,268 @@
+// Package tracing records spans in the manner of OpenTelemetry: a Tracer
+// starts root spans, child spans started with Start inherit the trace of the
+// span in the context, and finished spans are handed to an Exporter. Trace
+// context is propagated in the W3C traceparent header.
+//
+// Code that only creates child spans, such as services and repositories,
+// needs no tracer: without a span in the context Start returns a span that
+// records nothing.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type TraceID [16]byte
+
+func (id TraceID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+func (id TraceID) IsValid() bool {
+	return id != TraceID{}
+}
+
+type SpanID [8]byte
+
+func (id SpanID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+func (id SpanID) IsValid() bool {
+	return id != SpanID{}
+}
+
+// SpanContext identifies a span within its trace.
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+}
+
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+type SpanKind int
+
+// Span kinds, numbered as in OTLP.
+const (
+	SpanKindInternal SpanKind = 1
+	SpanKindServer   SpanKind = 2
+	SpanKindClient   SpanKind = 3
+)
+
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+func String(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+func Int(key string, value int) Attribute {
+	return Attribute{Key: key, Value: int64(value)}
+}
+
+// SpanData is a finished span as seen by an exporter.
+type SpanData struct {
+	Name        string
+	Kind        SpanKind
+	SpanContext SpanContext
+	Parent      SpanID
+	Start       time.Time
+	End         time.Time
+	Attributes  []Attribute
+	Error       string
+}
+
+// Exporter receives every finished, sampled span.
+type Exporter interface {
+	ExportSpan(span SpanData) error
+	Close() error
+}
+
+// Tracer starts root spans and exports the spans of their traces.
+type Tracer struct {
+	exporter Exporter
+	onError  func(error)
+}
+
+// NewTracer returns a tracer exporting to exporter. Export errors are passed
+// to onError, which may be nil.
+func NewTracer(exporter Exporter, onError func(error)) *Tracer {
+	return &Tracer{
+		exporter: exporter,
+		onError:  onError,
+	}
+}
+
+// StartRoot starts a span in the trace of remote, continuing a trace started
+// by the caller, or in a new sampled trace if remote is not valid. An
+// unsampled remote trace is propagated but not recorded.
+func (t *Tracer) StartRoot(ctx context.Context, remote SpanContext, name string, kind SpanKind, attributes ...Attribute) (context.Context, *Span) {
+	span := &Span{
+		tracer:     t,
+		name:       name,
+		kind:       kind,
+		start:      time.Now(),
+		attributes: attributes,
+	}
+
+	if remote.IsValid() {
+		span.spanContext = SpanContext{TraceID: remote.TraceID, SpanID: newSpanID(), Sampled: remote.Sampled}
+		span.parent = remote.SpanID
+	} else {
+		span.spanContext = SpanContext{TraceID: newTraceID(), SpanID: newSpanID(), Sampled: true}
+	}
+
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+type spanKey struct{}
+
+// Start starts a child of the span in ctx. Without one the returned span does
+// nothing and ctx is returned unchanged.
+func Start(ctx context.Context, name string, attributes ...Attribute) (context.Context, *Span) {
+	return StartKind(ctx, name, SpanKindInternal, attributes...)
+}
+
+// StartKind is like Start with a span kind other than internal.
+func StartKind(ctx context.Context, name string, kind SpanKind, attributes ...Attribute) (context.Context, *Span) {
+	parent, ok := ctx.Value(spanKey{}).(*Span)
+	if !ok {
+		return ctx, nil
+	}
+
+	span := &Span{
+		tracer: parent.tracer,
+		name:   name,
+		kind:   kind,
+		spanContext: SpanContext{
+			TraceID: parent.spanContext.TraceID,
+			SpanID:  newSpanID(),
+			Sampled: parent.spanContext.Sampled,
+		},
+		parent:     parent.spanContext.SpanID,
+		start:      time.Now(),
+		attributes: attributes,
+	}
+
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// SpanContextFromContext returns the context of the span in ctx, which is not
+// valid if there is none.
+func SpanContextFromContext(ctx context.Context) SpanContext {
+	if span, ok := ctx.Value(spanKey{}).(*Span); ok {
+		return span.spanContext
+	}
+	return SpanContext{}
+}
+
+// Span is an operation being timed. All methods may be called on a nil span,
+// which is what Start returns outside a trace.
+type Span struct {
+	tracer      *Tracer
+	name        string
+	kind        SpanKind
+	spanContext SpanContext
+	parent      SpanID
+	start       time.Time
+
+	mu         sync.Mutex
+	attributes []Attribute
+	err        string
+	ended      bool
+}
+
+// SetName renames the span, for names known only once the work is done, such
+// as the route of an HTTP request.
+func (s *Span) SetName(name string) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.name = name
+}
+
+func (s *Span) SetAttributes(attributes ...Attribute) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.attributes = append(s.attributes, attributes...)
+}
+
+// RecordError marks the span as failed. A nil error is ignored.
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.err = err.Error()
+}
+
+// End finishes the span and exports it if its trace is sampled. Only the
+// first call has an effect.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	data := SpanData{
+		Name:        s.name,
+		Kind:        s.kind,
+		SpanContext: s.spanContext,
+		Parent:      s.parent,
+		Start:       s.start,
+		End:         time.Now(),
+		Attributes:  append([]Attribute(nil), s.attributes...),
+		Error:       s.err,
+	}
+	s.mu.Unlock()
+
+	if !data.SpanContext.Sampled {
+		return
+	}
+	if err := s.tracer.exporter.ExportSpan(data); err != nil && s.tracer.onError != nil {
+		s.tracer.onError(fmt.Errorf("failed to export span %s: %w", data.Name, err))
+	}
+}
+
+func newTraceID() TraceID {
+	var id TraceID
+	for !id.IsValid() {
+		_, _ = rand.Read(id[:])
+	}
+	return id
+}
+
+func newSpanID() SpanID {
+	var id SpanID
+	for !id.IsValid() {
+		_, _ = rand.Read(id[:])
+	}
+	return id
+}
@@ -0,0 +1,24 @@
+CREATE TABLE budgets (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL,
+    service_name VARCHAR(255),
+    amount INTEGER NOT NULL CHECK (amount > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_budgets_user_id ON budgets(user_id);
+
+CREATE TABLE budget_alerts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    budget_id UUID NOT NULL REFERENCES budgets(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    threshold INTEGER NOT NULL,
+    spend INTEGER NOT NULL,
+    amount INTEGER NOT NULL,
+    month TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (budget_id, threshold, month)
+);
+
+CREATE INDEX idx_budget_alerts_user_id ON budget_alerts(user_id);
@@ -0,0 +1,6 @@
+UPDATE api_keys SET roles = ARRAY(
+    SELECT DISTINCT CASE role WHEN 'viewer' THEN 'read' WHEN 'support' THEN 'read' WHEN 'editor' THEN 'write' ELSE role END
+    FROM unnest(roles) AS role
+);
+
+ALTER TABLE api_keys RENAME COLUMN roles TO scopes;
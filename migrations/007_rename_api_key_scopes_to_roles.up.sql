@@ -0,0 +1,6 @@
+ALTER TABLE api_keys RENAME COLUMN scopes TO roles;
+
+UPDATE api_keys SET roles = ARRAY(
+    SELECT CASE role WHEN 'read' THEN 'viewer' WHEN 'write' THEN 'editor' ELSE role END
+    FROM unnest(roles) AS role
+);
@@ -0,0 +1,44 @@
+DROP POLICY IF EXISTS tenant_isolation ON outbox;
+ALTER TABLE outbox NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE outbox DISABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON webhook_deliveries;
+ALTER TABLE webhook_deliveries NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE webhook_deliveries DISABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON webhooks;
+ALTER TABLE webhooks NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE webhooks DISABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON budget_alerts;
+ALTER TABLE budget_alerts NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE budget_alerts DISABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON budgets;
+ALTER TABLE budgets NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE budgets DISABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON subscription_members;
+ALTER TABLE subscription_members NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE subscription_members DISABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON subscriptions;
+ALTER TABLE subscriptions NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE subscriptions DISABLE ROW LEVEL SECURITY;
+
+DROP INDEX IF EXISTS idx_api_keys_tenant_id;
+DROP INDEX IF EXISTS idx_outbox_pending;
+CREATE INDEX idx_outbox_pending ON outbox(next_attempt_at) WHERE published_at IS NULL;
+DROP INDEX IF EXISTS idx_webhook_deliveries_pending;
+CREATE INDEX idx_webhook_deliveries_pending ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+DROP INDEX IF EXISTS idx_webhooks_tenant_id;
+DROP INDEX IF EXISTS idx_budget_alerts_tenant_id_user_id;
+CREATE INDEX idx_budget_alerts_user_id ON budget_alerts(user_id);
+DROP INDEX IF EXISTS idx_budgets_tenant_id_user_id;
+CREATE INDEX idx_budgets_user_id ON budgets(user_id);
+DROP INDEX IF EXISTS idx_subscriptions_tenant_id_created_at;
+DROP INDEX IF EXISTS idx_subscriptions_tenant_id_user_id;
+CREATE INDEX idx_subscriptions_user_id ON subscriptions(user_id);
+
+ALTER TABLE api_keys DROP COLUMN tenant_id;
+ALTER TABLE outbox DROP COLUMN tenant_id;
+ALTER TABLE webhook_deliveries DROP COLUMN tenant_id;
+ALTER TABLE webhooks DROP COLUMN tenant_id;
+ALTER TABLE budget_alerts DROP COLUMN tenant_id;
+ALTER TABLE budgets DROP COLUMN tenant_id;
+ALTER TABLE subscription_members DROP COLUMN tenant_id;
+ALTER TABLE subscriptions DROP COLUMN tenant_id;
@@ -0,0 +1,74 @@
+-- Existing data belongs to the tenant of installations without tenants.
+ALTER TABLE subscriptions ADD COLUMN tenant_id VARCHAR(63) NOT NULL DEFAULT 'default' CHECK (tenant_id <> '');
+ALTER TABLE subscription_members ADD COLUMN tenant_id VARCHAR(63) NOT NULL DEFAULT 'default' CHECK (tenant_id <> '');
+ALTER TABLE budgets ADD COLUMN tenant_id VARCHAR(63) NOT NULL DEFAULT 'default' CHECK (tenant_id <> '');
+ALTER TABLE budget_alerts ADD COLUMN tenant_id VARCHAR(63) NOT NULL DEFAULT 'default' CHECK (tenant_id <> '');
+ALTER TABLE webhooks ADD COLUMN tenant_id VARCHAR(63) NOT NULL DEFAULT 'default' CHECK (tenant_id <> '');
+ALTER TABLE webhook_deliveries ADD COLUMN tenant_id VARCHAR(63) NOT NULL DEFAULT 'default' CHECK (tenant_id <> '');
+ALTER TABLE outbox ADD COLUMN tenant_id VARCHAR(63) NOT NULL DEFAULT 'default' CHECK (tenant_id <> '');
+ALTER TABLE api_keys ADD COLUMN tenant_id VARCHAR(63) NOT NULL DEFAULT 'default' CHECK (tenant_id <> '');
+
+-- New rows must name their tenant.
+ALTER TABLE subscriptions ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE subscription_members ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE budgets ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE budget_alerts ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE webhooks ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE webhook_deliveries ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE outbox ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE api_keys ALTER COLUMN tenant_id DROP DEFAULT;
+
+DROP INDEX idx_subscriptions_user_id;
+CREATE INDEX idx_subscriptions_tenant_id_user_id ON subscriptions(tenant_id, user_id);
+CREATE INDEX idx_subscriptions_tenant_id_created_at ON subscriptions(tenant_id, created_at);
+DROP INDEX idx_budgets_user_id;
+CREATE INDEX idx_budgets_tenant_id_user_id ON budgets(tenant_id, user_id);
+DROP INDEX idx_budget_alerts_user_id;
+CREATE INDEX idx_budget_alerts_tenant_id_user_id ON budget_alerts(tenant_id, user_id);
+CREATE INDEX idx_webhooks_tenant_id ON webhooks(tenant_id);
+DROP INDEX idx_webhook_deliveries_pending;
+CREATE INDEX idx_webhook_deliveries_pending ON webhook_deliveries(tenant_id, next_attempt_at) WHERE status = 'pending';
+DROP INDEX idx_outbox_pending;
+CREATE INDEX idx_outbox_pending ON outbox(tenant_id, next_attempt_at) WHERE published_at IS NULL;
+CREATE INDEX idx_api_keys_tenant_id ON api_keys(tenant_id);
+
+-- Row-level security is a second safeguard besides the tenant filter of every
+-- query: the service sets app.tenant_id to the tenant of each statement, and a
+-- statement sees and writes only the rows of that tenant. FORCE applies the
+-- policies to the owner of the tables, which the service usually connects as.
+-- api_keys has no policy because keys are looked up before the tenant of a
+-- request is known.
+ALTER TABLE subscriptions ENABLE ROW LEVEL SECURITY;
+ALTER TABLE subscriptions FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON subscriptions
+    USING (tenant_id = current_setting('app.tenant_id', true));
+
+ALTER TABLE subscription_members ENABLE ROW LEVEL SECURITY;
+ALTER TABLE subscription_members FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON subscription_members
+    USING (tenant_id = current_setting('app.tenant_id', true));
+
+ALTER TABLE budgets ENABLE ROW LEVEL SECURITY;
+ALTER TABLE budgets FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON budgets
+    USING (tenant_id = current_setting('app.tenant_id', true));
+
+ALTER TABLE budget_alerts ENABLE ROW LEVEL SECURITY;
+ALTER TABLE budget_alerts FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON budget_alerts
+    USING (tenant_id = current_setting('app.tenant_id', true));
+
+ALTER TABLE webhooks ENABLE ROW LEVEL SECURITY;
+ALTER TABLE webhooks FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON webhooks
+    USING (tenant_id = current_setting('app.tenant_id', true));
+
+ALTER TABLE webhook_deliveries ENABLE ROW LEVEL SECURITY;
+ALTER TABLE webhook_deliveries FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON webhook_deliveries
+    USING (tenant_id = current_setting('app.tenant_id', true));
+
+ALTER TABLE outbox ENABLE ROW LEVEL SECURITY;
+ALTER TABLE outbox FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON outbox
+    USING (tenant_id = current_setting('app.tenant_id', true));
@@ -0,0 +1,3 @@
+UPDATE api_keys SET roles = replace(replace(replace(roles, 'viewer', 'read'), 'support', 'read'), 'editor', 'write');
+
+ALTER TABLE api_keys RENAME COLUMN roles TO scopes;
@@ -0,0 +1,4 @@
+ALTER TABLE api_keys RENAME COLUMN scopes TO roles;
+
+-- Roles are comma-separated and no old name is part of another one.
+UPDATE api_keys SET roles = replace(replace(roles, 'read', 'viewer'), 'write', 'editor');
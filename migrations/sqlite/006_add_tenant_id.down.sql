@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_api_keys_tenant_id;
+DROP INDEX IF EXISTS idx_subscriptions_tenant_id_created_at;
+CREATE INDEX idx_subscriptions_created_at ON subscriptions(created_at);
+DROP INDEX IF EXISTS idx_subscriptions_tenant_id_user_id;
+CREATE INDEX idx_subscriptions_user_id ON subscriptions(user_id);
+
+ALTER TABLE api_keys DROP COLUMN tenant_id;
+ALTER TABLE outbox DROP COLUMN tenant_id;
+ALTER TABLE subscription_members DROP COLUMN tenant_id;
+ALTER TABLE subscriptions DROP COLUMN tenant_id;
@@ -0,0 +1,11 @@
+-- Existing data belongs to the tenant of installations without tenants.
+ALTER TABLE subscriptions ADD COLUMN tenant_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE subscription_members ADD COLUMN tenant_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE outbox ADD COLUMN tenant_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE api_keys ADD COLUMN tenant_id TEXT NOT NULL DEFAULT 'default';
+
+DROP INDEX idx_subscriptions_user_id;
+CREATE INDEX idx_subscriptions_tenant_id_user_id ON subscriptions(tenant_id, user_id);
+DROP INDEX idx_subscriptions_created_at;
+CREATE INDEX idx_subscriptions_tenant_id_created_at ON subscriptions(tenant_id, created_at);
+CREATE INDEX idx_api_keys_tenant_id ON api_keys(tenant_id);